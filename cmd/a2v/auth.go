@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/audio2videoAI/pkg/credentials"
+	"golang.org/x/term"
+)
+
+func runAuthCommand(args []string) error {
+	usage := fmt.Errorf("usage: a2v auth login|logout <provider> | a2v auth status (providers: %s)", strings.Join(credentials.Providers(), ", "))
+	if len(args) == 0 {
+		return usage
+	}
+	switch args[0] {
+	case "status":
+		return authStatus()
+	case "login", "logout":
+		if len(args) != 2 {
+			return usage
+		}
+		if _, err := credentials.ProviderKey(args[1]); err != nil {
+			return err
+		}
+		if args[0] == "login" {
+			return authLogin(args[1])
+		}
+		return authLogout(args[1])
+	default:
+		return usage
+	}
+}
+
+func authLogin(provider string) error {
+	token, err := readSecret(fmt.Sprintf("%s token: ", provider))
+	if err != nil {
+		return err
+	}
+	if token == "" {
+		return fmt.Errorf("empty token")
+	}
+	passphrase := os.Getenv(credentials.PassphraseEnv)
+	source, err := credentials.Save(provider, token, passphrase)
+	var keyringErr *credentials.KeyringError
+	if errors.As(err, &keyringErr) {
+		fmt.Fprintf(os.Stderr, "%v; storing the token in the encrypted credentials file.\n", err)
+		passphrase, err = readSecret("Credentials passphrase: ")
+		if err != nil {
+			return err
+		}
+		if passphrase == "" {
+			return credentials.ErrPassphraseRequired
+		}
+		source, err = credentials.Save(provider, token, passphrase)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s token stored in the %s\n", provider, source)
+	if source == credentials.SourceFile {
+		fmt.Printf("set %s to unlock %s at startup\n", credentials.PassphraseEnv, credentials.FilePath())
+	}
+	return nil
+}
+
+func authLogout(provider string) error {
+	err := credentials.Delete(provider, os.Getenv(credentials.PassphraseEnv))
+	if errors.Is(err, credentials.ErrPassphraseRequired) {
+		passphrase, readErr := readSecret("Credentials passphrase: ")
+		if readErr != nil {
+			return readErr
+		}
+		err = credentials.Delete(provider, passphrase)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s token removed\n", provider)
+	return nil
+}
+
+func authStatus() error {
+	for _, provider := range credentials.Providers() {
+		key, _ := credentials.ProviderKey(provider)
+		status := "not stored"
+		token, source, err := credentials.Lookup(provider)
+		switch {
+		case err != nil:
+			status = err.Error()
+		case token != "":
+			status = "stored in the " + source
+		}
+		if os.Getenv(key) != "" {
+			status += fmt.Sprintf(" (overridden by %s)", key)
+		}
+		fmt.Printf("%s: %s\n", provider, status)
+	}
+	return nil
+}
+
+var stdin = bufio.NewReader(os.Stdin)
+
+func readSecret(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		value, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		return strings.TrimSpace(string(value)), err
+	}
+	line, err := stdin.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
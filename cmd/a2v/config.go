@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/audio2videoAI/pkg/config"
+)
+
+type settingFlags map[string]string
+
+func (values settingFlags) String() string {
+	var pairs []string
+	for key, value := range values {
+		pairs = append(pairs, key+"="+value)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (values settingFlags) Set(pair string) error {
+	key, value, ok := strings.Cut(pair, "=")
+	if !ok || strings.TrimSpace(key) == "" {
+		return fmt.Errorf("expected KEY=VALUE, got %q", pair)
+	}
+	values[strings.ToUpper(strings.TrimSpace(key))] = value
+	return nil
+}
+
+func runConfigCommand(args []string, cfg config.Config) error {
+	if len(args) == 0 || args[0] != "show" {
+		return fmt.Errorf("usage: a2v config show")
+	}
+
+	profile := cfg.Profile
+	if profile == "" {
+		profile = "none"
+	}
+	files := strings.Join(cfg.Files, ", ")
+	if files == "" {
+		files = "none"
+	}
+	fmt.Printf("profile: %s\nfiles: %s\n\n", profile, files)
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, setting := range cfg.Settings {
+		fmt.Fprintf(writer, "%s\t%s\t(%s)\n", setting.Key, setting.Masked(), setting.Source)
+	}
+	return writer.Flush()
+}
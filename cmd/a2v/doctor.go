@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/audio2videoAI/internal/audio"
+	"github.com/audio2videoAI/pkg/config"
+)
+
+type check struct {
+	name   string
+	status string
+	detail string
+}
+
+const (
+	checkOK   = "ok"
+	checkWarn = "warn"
+	checkFail = "fail"
+)
+
+func runDoctorCommand(cfg config.Config, problems []config.Problem) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	var checks []check
+	for _, problem := range problems {
+		status := checkWarn
+		if problem.Severity == config.SeverityError {
+			status = checkFail
+		}
+		checks = append(checks, check{name: "config " + problem.Key, status: status, detail: problem.Message})
+	}
+	checks = append(checks,
+		checkFFmpeg(ctx, cfg),
+		checkDocker(ctx, cfg),
+		checkWhisperModel(cfg),
+		checkOutputDir(cfg),
+		checkToken("replicate token", cfg.ReplicateAPIToken, true),
+		checkToken("elevenlabs key", cfg.ElevenLabsAPIKey, false),
+	)
+
+	failed := 0
+	for _, result := range checks {
+		fmt.Printf("[%-4s] %s: %s\n", result.status, result.name, result.detail)
+		if result.status == checkFail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	return nil
+}
+
+func checkFFmpeg(ctx context.Context, cfg config.Config) check {
+	output, err := exec.CommandContext(ctx, cfg.FFmpegPath, "-version").Output()
+	if err != nil {
+		return check{name: "ffmpeg", status: checkFail, detail: fmt.Sprintf("%s not runnable (%v); install ffmpeg or set FFMPEG_PATH", cfg.FFmpegPath, err)}
+	}
+	line, _, _ := strings.Cut(string(output), "\n")
+	fields := strings.Fields(line)
+	if len(fields) < 3 || fields[1] != "version" {
+		return check{name: "ffmpeg", status: checkWarn, detail: "unrecognized version output: " + strings.TrimSpace(line)}
+	}
+	return check{name: "ffmpeg", status: checkOK, detail: "version " + fields[2]}
+}
+
+func checkDocker(ctx context.Context, cfg config.Config) check {
+	if !cfg.TranscribeEnabled {
+		return check{name: "docker", status: checkOK, detail: "not needed (TRANSCRIBE_ENABLED=false)"}
+	}
+	output, err := exec.CommandContext(ctx, cfg.WhisperDockerPath, "version", "--format", "{{.Server.Version}}").CombinedOutput()
+	if err != nil {
+		reason := strings.TrimSpace(string(output))
+		if reason == "" {
+			reason = err.Error()
+		}
+		return check{name: "docker", status: checkFail, detail: fmt.Sprintf("not available: %s; start Docker or set TRANSCRIBE_ENABLED=false", reason)}
+	}
+	return check{name: "docker", status: checkOK, detail: "server " + strings.TrimSpace(string(output))}
+}
+
+func checkWhisperModel(cfg config.Config) check {
+	if !cfg.TranscribeEnabled {
+		return check{name: "whisper model", status: checkOK, detail: "not needed (TRANSCRIBE_ENABLED=false)"}
+	}
+	path := audio.WhisperModelPath(audio.TranscribeConfig{Model: cfg.WhisperModel, ModelDir: cfg.WhisperModelDir})
+	if _, err := os.Stat(path); err != nil {
+		if cfg.WhisperAutoDownload {
+			return check{name: "whisper model", status: checkWarn, detail: path + " missing; it will be downloaded on the first transcription"}
+		}
+		return check{name: "whisper model", status: checkFail, detail: path + " missing; download it or set WHISPER_AUTO_DOWNLOAD=true"}
+	}
+	return check{name: "whisper model", status: checkOK, detail: path}
+}
+
+func checkOutputDir(cfg config.Config) check {
+	if err := os.MkdirAll(cfg.OutputDir, 0o755); err != nil {
+		return check{name: "output dir", status: checkFail, detail: err.Error()}
+	}
+	file, err := os.CreateTemp(cfg.OutputDir, ".doctor-*")
+	if err != nil {
+		return check{name: "output dir", status: checkFail, detail: fmt.Sprintf("%s is not writable: %v", cfg.OutputDir, err)}
+	}
+	file.Close()
+	os.Remove(file.Name())
+	return check{name: "output dir", status: checkOK, detail: cfg.OutputDir + " is writable"}
+}
+
+func checkToken(name, value string, required bool) check {
+	if value != "" {
+		return check{name: name, status: checkOK, detail: "set"}
+	}
+	if required {
+		return check{name: name, status: checkFail, detail: "missing"}
+	}
+	return check{name: name, status: checkOK, detail: "not set (optional)"}
+}
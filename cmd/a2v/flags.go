@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/audio2videoAI/internal/jobs"
+	"github.com/audio2videoAI/pkg/config"
+)
+
+type jobFlags struct {
+	audioPath      *string
+	lyrics         *string
+	preset         *string
+	style          *string
+	aspect         *string
+	duration       *int
+	templateName   *string
+	negativePrompt *string
+	seed           *int
+	params         *string
+	image          *string
+	subjectImages  *string
+	subjectPrompt  *string
+	subjectSeed    *int
+	variations     *int
+	sourceVideo    *string
+}
+
+func addJobFlags(flags *flag.FlagSet) jobFlags {
+	return jobFlags{
+		audioPath:      flags.String("audio", "", "audio file to use"),
+		lyrics:         flags.String("lyrics", "", "optional lyrics"),
+		preset:         flags.String("preset", "Hook", "outcome preset"),
+		style:          flags.String("style", "cinematic", "style preset"),
+		aspect:         flags.String("aspect", "", "aspect ratio (defaults to the preset, then 9:16)"),
+		duration:       flags.Int("duration", 0, "duration in seconds (defaults to the preset, then 30)"),
+		templateName:   flags.String("template", "", "prompt template name (defaults to the preset)"),
+		negativePrompt: flags.String("negative", "", "negative prompt"),
+		seed:           flags.Int("seed", -1, "seed for reproducible renders (-1 for random)"),
+		params:         flags.String("params", "", "extra model parameters as key=value, key=value"),
+		image:          flags.String("image", "", "reference image used as the first frame"),
+		subjectImages:  flags.String("subject-images", "", "comma separated subject reference images"),
+		subjectPrompt:  flags.String("subject-prompt", "", "subject description added to every prompt"),
+		subjectSeed:    flags.Int("subject-seed", -1, "seed used for every clip of the run (-1 to use -seed)"),
+		sourceVideo:    flags.String("source-video", "", "existing clip to restyle with the video-to-video model"),
+		variations:     flags.Int("variations", 1, "number of candidates rendered in parallel; the first one is exported"),
+	}
+}
+
+func (values jobFlags) jobInput(cfg config.Config, runner *jobs.Runner) (jobs.JobInput, error) {
+	if *values.audioPath == "" {
+		return jobs.JobInput{}, fmt.Errorf("-audio is required")
+	}
+	if *values.variations < 1 || *values.variations > jobs.MaxVariations {
+		return jobs.JobInput{}, fmt.Errorf("-variations must be between 1 and %d", jobs.MaxVariations)
+	}
+	params, err := jobs.ParseParams(*values.params)
+	if err != nil {
+		return jobs.JobInput{}, err
+	}
+	input := jobs.JobInput{
+		AudioPath:       *values.audioPath,
+		Lyrics:          *values.lyrics,
+		Preset:          *values.preset,
+		StylePreset:     *values.style,
+		AspectRatio:     *values.aspect,
+		DurationSeconds: *values.duration,
+		OutputDir:       cfg.OutputDir,
+		PromptTemplate:  *values.templateName,
+		NegativePrompt:  *values.negativePrompt,
+		Params:          params,
+		ReferenceImage:  *values.image,
+		Variations:      *values.variations,
+		SourceVideo:     *values.sourceVideo,
+		Subject: jobs.SubjectReference{
+			Images: jobs.ParseSubjectImages(*values.subjectImages),
+			Prompt: *values.subjectPrompt,
+		},
+	}
+	if *values.seed >= 0 {
+		seed := *values.seed
+		input.Seed = &seed
+	}
+	if *values.subjectSeed >= 0 {
+		seed := *values.subjectSeed
+		input.Subject.Seed = &seed
+	}
+	input = runner.ApplyPresets(input)
+	if input.AspectRatio == "" {
+		input.AspectRatio = "9:16"
+	}
+	if input.DurationSeconds <= 0 {
+		input.DurationSeconds = 30
+	}
+	return input, nil
+}
@@ -1,11 +1,11 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
+	"os"
 
-	"github.com/audio2videoAI/internal/ai/elevenlabs"
-	"github.com/audio2videoAI/internal/ai/replicate"
-	"github.com/audio2videoAI/internal/audio"
 	"github.com/audio2videoAI/internal/jobs"
 	"github.com/audio2videoAI/internal/tui"
 	"github.com/audio2videoAI/pkg/config"
@@ -15,23 +15,50 @@ import (
 
 func main() {
 	_ = godotenv.Load()
-	cfg := config.Load()
-	ell := elevenlabs.NewClient(cfg.ElevenLabsAPIKey, cfg.ElevenLabsBaseURL, cfg.ElevenLabsEnhancePath, cfg.HTTPTimeout)
-	replicateClient := replicate.NewClient(cfg.ReplicateAPIToken, cfg.ReplicateBaseURL, cfg.ReplicateModel, cfg.HTTPTimeout)
-	jobRunner := &jobs.Runner{
-		ElevenLabs: ell,
-		Replicate:  replicateClient,
-		Transcribe: audio.TranscribeConfig{
-			Enabled:      cfg.TranscribeEnabled,
-			DockerPath:   cfg.WhisperDockerPath,
-			DockerImage:  cfg.WhisperDockerImage,
-			Model:        cfg.WhisperModel,
-			ModelDir:     cfg.WhisperModelDir,
-			AutoDownload: cfg.WhisperAutoDownload,
-		},
-		FFmpegPath:   cfg.FFmpegPath,
-		PollInterval: cfg.JobPollInterval,
-		PreferWait:   cfg.ReplicatePreferWait,
+
+	globals := flag.NewFlagSet("a2v", flag.ExitOnError)
+	configPath := globals.String("config", "", "config file (defaults to ~/.config/a2v/config.yaml and ./a2v.yaml)")
+	profile := globals.String("profile", "", "config profile to apply")
+	overrides := settingFlags{}
+	globals.Var(overrides, "set", "override a setting as KEY=VALUE (repeatable)")
+	_ = globals.Parse(os.Args[1:])
+
+	cfg, problems, err := config.Load(config.Options{Path: *configPath, Profile: *profile, Overrides: overrides})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	args := globals.Args()
+	if len(args) > 0 && (args[0] == "doctor" || args[0] == "auth") {
+		if args[0] == "doctor" {
+			err = runDoctorCommand(cfg, problems)
+		} else {
+			err = runAuthCommand(args[1:])
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	for _, problem := range problems {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", problem.Severity, problem.Error())
+	}
+	if config.HasErrors(problems) {
+		fmt.Fprintln(os.Stderr, "fix the settings above or run `a2v doctor` for details")
+		os.Exit(1)
+	}
+	jobRunner, err := jobs.NewRunner(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "warning:", err)
+	}
+
+	if len(args) > 0 {
+		if err := runCommand(args, cfg, jobRunner); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
 	}
 
 	program := tea.NewProgram(tui.NewModel(cfg, jobRunner), tea.WithAltScreen())
@@ -39,3 +66,16 @@ func main() {
 		log.Fatal(err)
 	}
 }
+
+func runCommand(args []string, cfg config.Config, runner *jobs.Runner) error {
+	switch args[0] {
+	case "prompt":
+		return runPromptCommand(args[1:], cfg, runner)
+	case "render":
+		return runRenderCommand(args[1:], cfg, runner)
+	case "config":
+		return runConfigCommand(args[1:], cfg)
+	default:
+		return fmt.Errorf("unknown command: %s", args[0])
+	}
+}
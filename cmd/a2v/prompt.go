@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/audio2videoAI/internal/jobs"
+	"github.com/audio2videoAI/pkg/config"
+)
+
+func runPromptCommand(args []string, cfg config.Config, runner *jobs.Runner) error {
+	if len(args) == 0 || args[0] != "preview" {
+		return fmt.Errorf("usage: a2v prompt preview -audio <path> [flags]")
+	}
+
+	flags := flag.NewFlagSet("prompt preview", flag.ContinueOnError)
+	job := addJobFlags(flags)
+	transcribe := flags.Bool("transcribe", cfg.TranscribeEnabled, "run Whisper transcription")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	input, err := job.jobInput(cfg, runner)
+	if err != nil {
+		return err
+	}
+
+	preview := *runner
+	preview.ElevenLabs = nil
+	preview.Transcribe.Enabled = *transcribe
+
+	events, wait := printEvents()
+	preparation, err := preview.Prepare(context.Background(), input, events)
+	close(events)
+	wait()
+	if err != nil {
+		return err
+	}
+
+	prompt, err := preview.BuildPrompt(input, preparation)
+	if err != nil {
+		return err
+	}
+	fmt.Println(prompt)
+	return nil
+}
+
+func printEvents() (chan jobs.Event, func()) {
+	events := make(chan jobs.Event)
+	printed := make(chan struct{})
+	go func() {
+		for event := range events {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", event.Stage, event.Message)
+		}
+		close(printed)
+	}()
+	return events, func() { <-printed }
+}
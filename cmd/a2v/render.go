@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/audio2videoAI/internal/jobs"
+	"github.com/audio2videoAI/pkg/config"
+)
+
+func runRenderCommand(args []string, cfg config.Config, runner *jobs.Runner) error {
+	flags := flag.NewFlagSet("render", flag.ContinueOnError)
+	job := addJobFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	input, err := job.jobInput(cfg, runner)
+	if err != nil {
+		return err
+	}
+
+	events, wait := printEvents()
+	result, err := runner.Run(context.Background(), input, events)
+	close(events)
+	wait()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("video: %s\nmetadata: %s\n", result.FinalPath, result.MetaPath)
+	if len(result.Variations) > 1 {
+		for index, variation := range result.Variations {
+			fmt.Printf("variation %d: %s\n", index+1, variation.Path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type Client struct {
+	APIKey     string
+	BaseURL    string
+	Model      string
+	HTTPClient *http.Client
+}
+
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model       string    `json:"model,omitempty"`
+	Messages    []Message `json:"messages"`
+	Temperature float64   `json:"temperature"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message Message `json:"message"`
+	} `json:"choices"`
+}
+
+func NewClient(apiKey, baseURL, model string, timeout time.Duration) *Client {
+	if baseURL == "" {
+		baseURL = "http://localhost:8080/v1"
+	}
+	return &Client{
+		APIKey:     apiKey,
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		Model:      model,
+		HTTPClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (client *Client) Chat(ctx context.Context, messages []Message) (string, error) {
+	payload, err := json.Marshal(chatRequest{Model: client.Model, Messages: messages, Temperature: 0.7})
+	if err != nil {
+		return "", err
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, client.BaseURL+"/chat/completions", bytes.NewBuffer(payload))
+	if err != nil {
+		return "", err
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+	if client.APIKey != "" {
+		httpRequest.Header.Set("Authorization", "Bearer "+client.APIKey)
+	}
+
+	response, err := client.HTTPClient.Do(httpRequest)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= http.StatusBadRequest {
+		body, _ := io.ReadAll(response.Body)
+		return "", fmt.Errorf("llm chat error: %s", string(body))
+	}
+
+	var chat chatResponse
+	if err := json.NewDecoder(response.Body).Decode(&chat); err != nil {
+		return "", err
+	}
+	if len(chat.Choices) == 0 {
+		return "", fmt.Errorf("llm chat error: empty response")
+	}
+	return chat.Choices[0].Message.Content, nil
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -16,14 +17,26 @@ type Client struct {
 	BaseURL    string
 	Model      string
 	HTTPClient *http.Client
+
+	schemas *schemaCache
+}
+
+type schemaCache struct {
+	mu      sync.Mutex
+	schemas map[string]InputSchema
 }
 
 type Prediction struct {
-	ID     string `json:"id"`
-	Status string `json:"status"`
-	Output any    `json:"output"`
-	Error  any    `json:"error"`
-	Logs   string `json:"logs"`
+	ID      string            `json:"id"`
+	Status  string            `json:"status"`
+	Output  any               `json:"output"`
+	Error   any               `json:"error"`
+	Logs    string            `json:"logs"`
+	Metrics PredictionMetrics `json:"metrics"`
+}
+
+type PredictionMetrics struct {
+	PredictTime float64 `json:"predict_time"`
 }
 
 type PredictionRequest struct {
@@ -42,6 +55,20 @@ func NewClient(apiToken, baseURL, model string, timeout time.Duration) *Client {
 		BaseURL:    strings.TrimRight(baseURL, "/"),
 		Model:      model,
 		HTTPClient: &http.Client{Timeout: timeout},
+		schemas:    &schemaCache{schemas: map[string]InputSchema{}},
+	}
+}
+
+func (client *Client) ForModel(model string) *Client {
+	if model == "" || model == client.Model {
+		return client
+	}
+	return &Client{
+		APIToken:   client.APIToken,
+		BaseURL:    client.BaseURL,
+		Model:      model,
+		HTTPClient: client.HTTPClient,
+		schemas:    client.schemas,
 	}
 }
 
@@ -74,7 +101,7 @@ func (client *Client) SubmitPrediction(ctx context.Context, request PredictionRe
 
 	if response.StatusCode >= http.StatusBadRequest {
 		body, _ := io.ReadAll(response.Body)
-		return Prediction{}, fmt.Errorf("replicate submit error: %s", string(body))
+		return Prediction{}, newAPIError("submit", response.StatusCode, body)
 	}
 
 	var prediction Prediction
@@ -100,7 +127,7 @@ func (client *Client) FetchPrediction(ctx context.Context, id string) (Predictio
 
 	if response.StatusCode >= http.StatusBadRequest {
 		body, _ := io.ReadAll(response.Body)
-		return Prediction{}, fmt.Errorf("replicate status error: %s", string(body))
+		return Prediction{}, newAPIError("status", response.StatusCode, body)
 	}
 
 	var prediction Prediction
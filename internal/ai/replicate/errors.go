@@ -0,0 +1,74 @@
+package replicate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type APIError struct {
+	Operation     string
+	StatusCode    int
+	Body          string
+	Title         string
+	Detail        string
+	InvalidFields []InvalidField
+}
+
+type InvalidField struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+type PredictionError struct {
+	ID     string
+	Status string
+	Detail any
+	Logs   string
+}
+
+func newAPIError(operation string, statusCode int, body []byte) *APIError {
+	apiErr := &APIError{Operation: operation, StatusCode: statusCode, Body: strings.TrimSpace(string(body))}
+	var parsed struct {
+		Title         string         `json:"title"`
+		Detail        string         `json:"detail"`
+		InvalidFields []InvalidField `json:"invalid_fields"`
+	}
+	if json.Unmarshal(body, &parsed) == nil {
+		apiErr.Title = parsed.Title
+		apiErr.Detail = parsed.Detail
+		apiErr.InvalidFields = parsed.InvalidFields
+	}
+	return apiErr
+}
+
+func (err *APIError) Error() string {
+	if err.Title == "" && err.Detail == "" {
+		return fmt.Sprintf("replicate %s error: %s", err.Operation, err.Body)
+	}
+	message := err.Title
+	if err.Detail != "" && err.Detail != err.Title {
+		if message != "" {
+			message += ": "
+		}
+		message += err.Detail
+	}
+	return fmt.Sprintf("replicate %s error (HTTP %d): %s", err.Operation, err.StatusCode, message)
+}
+
+func (err *APIError) PrettyBody() string {
+	var indented bytes.Buffer
+	if json.Indent(&indented, []byte(err.Body), "", "  ") != nil {
+		return err.Body
+	}
+	return indented.String()
+}
+
+func (err *PredictionError) Error() string {
+	message := "replicate job " + strings.ToLower(err.Status)
+	if err.Detail != nil {
+		message += fmt.Sprintf(": %v", err.Detail)
+	}
+	return message
+}
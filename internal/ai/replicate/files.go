@@ -0,0 +1,115 @@
+package replicate
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+const maxDataURIBytes = 256 * 1024
+
+type uploadResponse struct {
+	URLs struct {
+		Get string `json:"get"`
+	} `json:"urls"`
+}
+
+func (client *Client) FileInput(ctx context.Context, path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("%s is a directory", path)
+	}
+	if info.Size() <= maxDataURIBytes {
+		return DataURI(path)
+	}
+	return client.UploadFile(ctx, path)
+}
+
+func DataURI(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("data:%s;base64,%s", contentType(path), base64.StdEncoding.EncodeToString(content)), nil
+}
+
+func (client *Client) UploadFile(ctx context.Context, path string) (string, error) {
+	if client.APIToken == "" {
+		return "", fmt.Errorf("replicate api token is required")
+	}
+
+	reqBody, writer := io.Pipe()
+	multipartWriter := multipart.NewWriter(writer)
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, client.BaseURL+"/files", reqBody)
+	if err != nil {
+		return "", err
+	}
+	httpRequest.Header.Set("Authorization", "Bearer "+client.APIToken)
+	httpRequest.Header.Set("Content-Type", multipartWriter.FormDataContentType())
+
+	errorChan := make(chan error, 1)
+	go func() {
+		defer writer.Close()
+		defer multipartWriter.Close()
+
+		file, err := os.Open(path)
+		if err != nil {
+			errorChan <- err
+			return
+		}
+		defer file.Close()
+
+		part, err := multipartWriter.CreateFormFile("content", filepath.Base(path))
+		if err != nil {
+			errorChan <- err
+			return
+		}
+		if _, err := io.Copy(part, file); err != nil {
+			errorChan <- err
+			return
+		}
+		errorChan <- nil
+	}()
+
+	response, err := client.HTTPClient.Do(httpRequest)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	if err := <-errorChan; err != nil {
+		return "", err
+	}
+
+	if response.StatusCode >= http.StatusBadRequest {
+		body, _ := io.ReadAll(response.Body)
+		return "", newAPIError("upload", response.StatusCode, body)
+	}
+
+	var upload uploadResponse
+	if err := json.NewDecoder(response.Body).Decode(&upload); err != nil {
+		return "", err
+	}
+	if upload.URLs.Get == "" {
+		return "", fmt.Errorf("replicate upload error: file url missing")
+	}
+	return upload.URLs.Get, nil
+}
+
+func contentType(path string) string {
+	if value := mime.TypeByExtension(filepath.Ext(path)); value != "" {
+		return value
+	}
+	return "application/octet-stream"
+}
@@ -0,0 +1,282 @@
+package replicate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+type InputSchema struct {
+	Properties map[string]SchemaProperty `json:"properties"`
+	Required   []string                  `json:"required"`
+}
+
+type SchemaProperty struct {
+	Type        string           `json:"type"`
+	Description string           `json:"description"`
+	Default     any              `json:"default"`
+	Enum        []any            `json:"enum"`
+	Minimum     *float64         `json:"minimum"`
+	Maximum     *float64         `json:"maximum"`
+	AllOf       []SchemaProperty `json:"allOf"`
+	Ref         string           `json:"$ref"`
+}
+
+type modelResponse struct {
+	LatestVersion struct {
+		ID            string `json:"id"`
+		OpenAPISchema struct {
+			Components struct {
+				Schemas map[string]json.RawMessage `json:"schemas"`
+			} `json:"components"`
+		} `json:"openapi_schema"`
+	} `json:"latest_version"`
+}
+
+func (schema InputSchema) Known() bool {
+	return len(schema.Properties) > 0
+}
+
+func (schema InputSchema) Has(key string) bool {
+	_, ok := schema.Properties[key]
+	return ok
+}
+
+func (schema InputSchema) Keys() []string {
+	keys := make([]string, 0, len(schema.Properties))
+	for key := range schema.Properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (schema InputSchema) Enum(key string) []string {
+	property, ok := schema.Properties[key]
+	if !ok {
+		return nil
+	}
+	values := make([]string, 0, len(property.Enum))
+	for _, value := range property.Enum {
+		values = append(values, fmt.Sprint(value))
+	}
+	return values
+}
+
+func (schema InputSchema) ValidateValue(key string, value any) error {
+	property, ok := schema.Properties[key]
+	if !ok {
+		return fmt.Errorf("unsupported input %q", key)
+	}
+	return property.validate(value)
+}
+
+func (schema InputSchema) Describe(key string) string {
+	property, ok := schema.Properties[key]
+	if !ok {
+		return ""
+	}
+	if values := schema.Enum(key); len(values) > 0 {
+		return "one of " + strings.Join(values, ", ")
+	}
+	switch {
+	case property.Minimum != nil && property.Maximum != nil:
+		return fmt.Sprintf("%v to %v", *property.Minimum, *property.Maximum)
+	case property.Minimum != nil:
+		return fmt.Sprintf("at least %v", *property.Minimum)
+	case property.Maximum != nil:
+		return fmt.Sprintf("at most %v", *property.Maximum)
+	}
+	return property.Type
+}
+
+func (schema InputSchema) Validate(input map[string]any) error {
+	var problems []error
+	for _, key := range schema.Required {
+		if _, ok := input[key]; !ok {
+			problems = append(problems, fmt.Errorf("missing required input %q", key))
+		}
+	}
+
+	keys := make([]string, 0, len(input))
+	for key := range input {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		property, ok := schema.Properties[key]
+		if !ok {
+			problems = append(problems, fmt.Errorf("unsupported input %q", key))
+			continue
+		}
+		if err := property.validate(input[key]); err != nil {
+			problems = append(problems, fmt.Errorf("input %q: %w", key, err))
+		}
+	}
+	return errors.Join(problems...)
+}
+
+func (property SchemaProperty) validate(value any) error {
+	number, isNumber := numericValue(value)
+	switch property.Type {
+	case "integer":
+		if !isNumber || number != math.Trunc(number) {
+			return fmt.Errorf("expected integer, got %v", value)
+		}
+	case "number":
+		if !isNumber {
+			return fmt.Errorf("expected number, got %v", value)
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("expected string, got %v", value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("expected boolean, got %v", value)
+		}
+	}
+
+	if len(property.Enum) > 0 {
+		allowed := make([]string, 0, len(property.Enum))
+		matched := false
+		for _, option := range property.Enum {
+			allowed = append(allowed, fmt.Sprint(option))
+			if fmt.Sprint(option) == fmt.Sprint(value) {
+				matched = true
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%v is not one of %s", value, strings.Join(allowed, ", "))
+		}
+	}
+	if isNumber && property.Minimum != nil && number < *property.Minimum {
+		return fmt.Errorf("%v is below minimum %v", value, *property.Minimum)
+	}
+	if isNumber && property.Maximum != nil && number > *property.Maximum {
+		return fmt.Errorf("%v is above maximum %v", value, *property.Maximum)
+	}
+	return nil
+}
+
+func (client *Client) FetchInputSchema(ctx context.Context) (InputSchema, error) {
+	schema, ok := client.schemas.get(client.Model)
+	if ok {
+		return schema, nil
+	}
+
+	url := fmt.Sprintf("%s/models/%s", client.BaseURL, client.Model)
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return InputSchema{}, err
+	}
+	httpRequest.Header.Set("Authorization", "Bearer "+client.APIToken)
+
+	response, err := client.HTTPClient.Do(httpRequest)
+	if err != nil {
+		return InputSchema{}, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= http.StatusBadRequest {
+		body, _ := io.ReadAll(response.Body)
+		return InputSchema{}, newAPIError("schema", response.StatusCode, body)
+	}
+
+	var model modelResponse
+	if err := json.NewDecoder(response.Body).Decode(&model); err != nil {
+		return InputSchema{}, err
+	}
+	schema, err = parseInputSchema(model.LatestVersion.OpenAPISchema.Components.Schemas)
+	if err != nil {
+		return InputSchema{}, fmt.Errorf("replicate schema error: model %s: %w", client.Model, err)
+	}
+
+	client.schemas.put(client.Model, schema)
+	return schema, nil
+}
+
+func (cache *schemaCache) get(model string) (InputSchema, bool) {
+	if cache == nil {
+		return InputSchema{}, false
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	schema, ok := cache.schemas[model]
+	return schema, ok
+}
+
+func (cache *schemaCache) put(model string, schema InputSchema) {
+	if cache == nil {
+		return
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.schemas[model] = schema
+}
+
+func (client *Client) ValidateRequest(ctx context.Context, request PredictionRequest) error {
+	schema, err := client.FetchInputSchema(ctx)
+	if err != nil {
+		return err
+	}
+	if err := schema.Validate(request.Input); err != nil {
+		return fmt.Errorf("replicate input invalid for %s: %w", client.Model, err)
+	}
+	return nil
+}
+
+func parseInputSchema(schemas map[string]json.RawMessage) (InputSchema, error) {
+	raw, ok := schemas["Input"]
+	if !ok {
+		return InputSchema{}, fmt.Errorf("no input schema")
+	}
+	var schema InputSchema
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return InputSchema{}, err
+	}
+	for key, property := range schema.Properties {
+		for _, ref := range property.AllOf {
+			name := strings.TrimPrefix(ref.Ref, "#/components/schemas/")
+			referenced, ok := schemas[name]
+			if ref.Ref == "" || !ok {
+				continue
+			}
+			var resolved SchemaProperty
+			if err := json.Unmarshal(referenced, &resolved); err != nil {
+				return InputSchema{}, err
+			}
+			if property.Type == "" {
+				property.Type = resolved.Type
+			}
+			if len(property.Enum) == 0 {
+				property.Enum = resolved.Enum
+			}
+		}
+		schema.Properties[key] = property
+	}
+	return schema, nil
+}
+
+func numericValue(value any) (float64, bool) {
+	switch typed := value.(type) {
+	case int:
+		return float64(typed), true
+	case int64:
+		return float64(typed), true
+	case float64:
+		return typed, true
+	case float32:
+		return float64(typed), true
+	case json.Number:
+		parsed, err := typed.Float64()
+		return parsed, err == nil
+	}
+	return 0, false
+}
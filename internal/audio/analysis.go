@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"math"
 	"os/exec"
 	"regexp"
 	"strconv"
@@ -15,8 +16,25 @@ type Analysis struct {
 	MeanVolume float64
 	MaxVolume  float64
 	Duration   float64
+	HookTime   float64
+	Envelope   []EnvelopePoint
+	Onsets     []float64
+	Beats      []float64
+	Sections   []Section
 }
 
+type EnvelopePoint struct {
+	Time float64
+	RMS  float64
+}
+
+const (
+	envelopeWindowSeconds = 0.1
+	silenceDB             = -90.0
+	onsetRiseDB           = 6.0
+	onsetGapSeconds       = 0.2
+)
+
 func Analyze(ctx context.Context, ffmpegPath, inputPath string) (Analysis, error) {
 	if ffmpegPath == "" {
 		ffmpegPath = "ffmpeg"
@@ -42,14 +60,164 @@ func Analyze(ctx context.Context, ffmpegPath, inputPath string) (Analysis, error
 		bpm = parseBPM(stderrBpm.String())
 	}
 
+	var envelope []EnvelopePoint
+	if points, err := analyzeEnvelope(ctx, ffmpegPath, inputPath); err == nil {
+		envelope = points
+	}
+
+	onsets := detectOnsets(envelope)
 	return Analysis{
 		BPM:        bpm,
 		MeanVolume: meanVol,
 		MaxVolume:  maxVol,
 		Duration:   duration,
+		HookTime:   hookTime(envelope),
+		Envelope:   envelope,
+		Onsets:     onsets,
+		Beats:      beatGrid(bpm, onsets, duration),
+		Sections:   DetectSections(envelope),
 	}, nil
 }
 
+func detectOnsets(envelope []EnvelopePoint) []float64 {
+	if len(envelope) < 2 {
+		return nil
+	}
+	var total float64
+	for _, point := range envelope {
+		total += point.RMS
+	}
+	mean := total / float64(len(envelope))
+
+	var onsets []float64
+	last := -onsetGapSeconds
+	for index := 1; index < len(envelope); index++ {
+		point := envelope[index]
+		rise := point.RMS - envelope[index-1].RMS
+		if rise >= onsetRiseDB && point.RMS >= mean && point.Time-last >= onsetGapSeconds {
+			onsets = append(onsets, point.Time)
+			last = point.Time
+		}
+	}
+	return onsets
+}
+
+func beatGrid(bpm float64, onsets []float64, duration float64) []float64 {
+	if bpm <= 0 || duration <= 0 {
+		return nil
+	}
+	period := 60 / bpm
+	phase := 0.0
+	if len(onsets) > 0 {
+		phase = math.Mod(onsets[0], period)
+	}
+	var beats []float64
+	for beat := phase; beat < duration; beat += period {
+		beats = append(beats, beat)
+	}
+	return beats
+}
+
+func analyzeEnvelope(ctx context.Context, ffmpegPath, inputPath string) ([]EnvelopePoint, error) {
+	filter := fmt.Sprintf(
+		"aresample=22050,asetnsamples=n=%d:p=0,astats=metadata=1:reset=1,ametadata=print:key=lavfi.astats.Overall.RMS_level:file=-",
+		int(22050*envelopeWindowSeconds),
+	)
+	cmd := exec.CommandContext(ctx, ffmpegPath, "-i", inputPath, "-filter:a", filter, "-f", "null", "-")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("envelope analysis failed: %s", strings.TrimSpace(stderr.String()))
+	}
+	return parseEnvelope(stdout.String()), nil
+}
+
+func parseEnvelope(output string) []EnvelopePoint {
+	var points []EnvelopePoint
+	current := -1.0
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if idx := strings.Index(line, "pts_time:"); idx != -1 {
+			value, err := strconv.ParseFloat(strings.TrimSpace(line[idx+len("pts_time:"):]), 64)
+			if err == nil {
+				current = value
+			}
+			continue
+		}
+		if current < 0 || !strings.HasPrefix(line, "lavfi.astats.Overall.RMS_level=") {
+			continue
+		}
+		rms, err := strconv.ParseFloat(strings.TrimPrefix(line, "lavfi.astats.Overall.RMS_level="), 64)
+		if err != nil || math.IsInf(rms, 0) || math.IsNaN(rms) {
+			rms = silenceDB
+		}
+		points = append(points, EnvelopePoint{Time: current, RMS: math.Max(rms, silenceDB)})
+		current = -1
+	}
+	return points
+}
+
+func (analysis Analysis) HookWithin(end float64) (float64, bool) {
+	var window []EnvelopePoint
+	for _, point := range analysis.Envelope {
+		if point.Time < end {
+			window = append(window, point)
+		}
+	}
+	if len(window) == 0 {
+		return 0, false
+	}
+	return hookTime(window), true
+}
+
+func hookTime(envelope []EnvelopePoint) float64 {
+	buckets := secondBuckets(envelope)
+	if len(buckets) == 0 {
+		return 0
+	}
+	best, bestRise := 0, 0.0
+	loudest := 0
+	for index := range buckets {
+		if buckets[index] > buckets[loudest] {
+			loudest = index
+		}
+		if index == 0 {
+			continue
+		}
+		if rise := buckets[index] - buckets[index-1]; rise > bestRise {
+			best, bestRise = index, rise
+		}
+	}
+	if bestRise <= 0 {
+		return float64(loudest)
+	}
+	return float64(best)
+}
+
+func secondBuckets(envelope []EnvelopePoint) []float64 {
+	var sums []float64
+	var counts []int
+	for _, point := range envelope {
+		index := int(point.Time)
+		for len(sums) <= index {
+			sums = append(sums, 0)
+			counts = append(counts, 0)
+		}
+		sums[index] += point.RMS
+		counts[index]++
+	}
+	buckets := make([]float64, len(sums))
+	for index := range sums {
+		if counts[index] == 0 {
+			buckets[index] = silenceDB
+			continue
+		}
+		buckets[index] = sums[index] / float64(counts[index])
+	}
+	return buckets
+}
+
 func parseFFmpegValue(output, prefix, suffix string) float64 {
 	idx := strings.Index(output, prefix)
 	if idx == -1 {
@@ -0,0 +1,102 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type Info struct {
+	Format     string
+	Codec      string
+	SampleRate int
+	Channels   string
+	Duration   float64
+}
+
+var (
+	inputFormatPattern = regexp.MustCompile(`Input #0, ([^,]+(?:,[^,\s]+)*), from`)
+	audioStreamPattern = regexp.MustCompile(`Stream #\d+:\d+.*: Audio: ([^,\s]+)[^,]*(?:, (\d+) Hz)?(?:, ([^,]+))?`)
+)
+
+func AudioExtensions() []string {
+	return []string{".aac", ".aiff", ".flac", ".m4a", ".mp3", ".ogg", ".opus", ".wav", ".webm"}
+}
+
+func IsAudioFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, candidate := range AudioExtensions() {
+		if ext == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+func Probe(ctx context.Context, ffmpegPath, path string) (Info, error) {
+	if err := ValidateAudioPath(path); err != nil {
+		return Info{}, err
+	}
+	if ffmpegPath == "" {
+		ffmpegPath = "ffmpeg"
+	}
+	cmd := exec.CommandContext(ctx, ffmpegPath, "-hide_banner", "-i", path)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if _, ok := err.(*exec.ExitError); !ok {
+			return Info{}, fmt.Errorf("probe audio: %w", err)
+		}
+	}
+	return parseProbe(stderr.String())
+}
+
+func parseProbe(output string) (Info, error) {
+	stream := audioStreamPattern.FindStringSubmatch(output)
+	if stream == nil {
+		if line := lastLine(output); line != "" {
+			return Info{}, fmt.Errorf("no audio stream found: %s", line)
+		}
+		return Info{}, fmt.Errorf("no audio stream found")
+	}
+	info := Info{
+		Codec:    stream[1],
+		Channels: strings.TrimSpace(stream[3]),
+		Duration: parseDurationFromLog(output),
+	}
+	info.SampleRate, _ = strconv.Atoi(stream[2])
+	if match := inputFormatPattern.FindStringSubmatch(output); match != nil {
+		info.Format = match[1]
+	}
+	return info, nil
+}
+
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+func (info Info) String() string {
+	parts := []string{formatSeconds(info.Duration)}
+	if info.Format != "" {
+		parts = append(parts, info.Format)
+	}
+	codec := info.Codec
+	if info.SampleRate > 0 {
+		codec += fmt.Sprintf(" %d Hz", info.SampleRate)
+	}
+	if info.Channels != "" {
+		codec += " " + info.Channels
+	}
+	return strings.Join(append(parts, codec), " · ")
+}
+
+func formatSeconds(seconds float64) string {
+	total := int(seconds + 0.5)
+	return fmt.Sprintf("%d:%02d", total/60, total%60)
+}
@@ -0,0 +1,73 @@
+package audio
+
+const minSectionSeconds = 4
+
+type Section struct {
+	Label  string
+	Start  float64
+	End    float64
+	Energy float64
+}
+
+func DetectSections(envelope []EnvelopePoint) []Section {
+	buckets := secondBuckets(envelope)
+	if len(buckets) == 0 {
+		return nil
+	}
+
+	var total float64
+	for _, level := range buckets {
+		total += level
+	}
+	mean := total / float64(len(buckets))
+
+	type run struct {
+		high  bool
+		start int
+		end   int
+	}
+	var runs []run
+	for index, level := range buckets {
+		high := level >= mean
+		if len(runs) > 0 && runs[len(runs)-1].high == high {
+			runs[len(runs)-1].end = index + 1
+			continue
+		}
+		runs = append(runs, run{high: high, start: index, end: index + 1})
+	}
+
+	var merged []run
+	for _, current := range runs {
+		if len(merged) > 0 && (current.end-current.start < minSectionSeconds || merged[len(merged)-1].high == current.high) {
+			merged[len(merged)-1].end = current.end
+			continue
+		}
+		merged = append(merged, current)
+	}
+
+	sections := make([]Section, 0, len(merged))
+	for index, current := range merged {
+		var energy float64
+		for _, level := range buckets[current.start:current.end] {
+			energy += level
+		}
+		energy /= float64(current.end - current.start)
+
+		label := "verse"
+		switch {
+		case current.high:
+			label = "chorus"
+		case index == 0:
+			label = "intro"
+		case index == len(merged)-1:
+			label = "outro"
+		}
+		sections = append(sections, Section{
+			Label:  label,
+			Start:  float64(current.start),
+			End:    float64(current.end),
+			Energy: energy,
+		})
+	}
+	return sections
+}
@@ -47,7 +47,7 @@ func Transcribe(ctx context.Context, config TranscribeConfig, audioPath, outputD
 	}
 	config.ModelDir = modelDir
 
-	modelPath := filepath.Join(config.ModelDir, fmt.Sprintf("ggml-%s.bin", config.Model))
+	modelPath := WhisperModelPath(config)
 	if _, err := os.Stat(modelPath); err != nil {
 		if !config.AutoDownload {
 			return "", "", fmt.Errorf("whisper model not found: %s", modelPath)
@@ -107,6 +107,18 @@ func Transcribe(ctx context.Context, config TranscribeConfig, audioPath, outputD
 	return strings.TrimSpace(string(content)), finalPath, nil
 }
 
+func WhisperModelPath(config TranscribeConfig) string {
+	model := config.Model
+	if model == "" {
+		model = "small"
+	}
+	modelDir := config.ModelDir
+	if modelDir == "" {
+		modelDir = "./models"
+	}
+	return filepath.Join(modelDir, fmt.Sprintf("ggml-%s.bin", model))
+}
+
 func downloadModel(ctx context.Context, config TranscribeConfig) error {
 	cmd := exec.CommandContext(
 		ctx,
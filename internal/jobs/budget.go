@@ -0,0 +1,74 @@
+package jobs
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const maxKeyLines = 6
+
+func KeyLines(text string, max int) []string {
+	if max <= 0 {
+		max = maxKeyLines
+	}
+	type line struct {
+		text  string
+		count int
+		first int
+	}
+	seen := map[string]*line{}
+	var ordered []*line
+	for index, raw := range strings.FieldsFunc(text, func(r rune) bool { return r == '\n' || r == '\r' }) {
+		raw = strings.TrimSpace(raw)
+		key := normalizeLine(raw)
+		if key == "" {
+			continue
+		}
+		if existing, ok := seen[key]; ok {
+			existing.count++
+			continue
+		}
+		entry := &line{text: raw, count: 1, first: index}
+		seen[key] = entry
+		ordered = append(ordered, entry)
+	}
+
+	ranked := append([]*line(nil), ordered...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].count > ranked[j].count
+	})
+	if len(ranked) > max {
+		ranked = ranked[:max]
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].first < ranked[j].first
+	})
+
+	lines := make([]string, 0, len(ranked))
+	for _, entry := range ranked {
+		lines = append(lines, entry.text)
+	}
+	return lines
+}
+
+func fitPrompt(prompt string, maxChars int) (string, bool) {
+	if maxChars <= 0 || len(prompt) <= maxChars {
+		return prompt, false
+	}
+	cut := strings.ToValidUTF8(prompt[:maxChars], "")
+	if index := strings.LastIndexAny(cut, " ,"); index > maxChars/2 {
+		cut = cut[:index]
+	}
+	return strings.TrimRight(cut, " ,"), true
+}
+
+func normalizeLine(value string) string {
+	var builder strings.Builder
+	for _, r := range strings.ToLower(value) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsSpace(r) {
+			builder.WriteRune(r)
+		}
+	}
+	return strings.Join(strings.Fields(builder.String()), " ")
+}
@@ -0,0 +1,56 @@
+package jobs
+
+import (
+	"github.com/audio2videoAI/internal/ai/elevenlabs"
+	"github.com/audio2videoAI/internal/ai/llm"
+	"github.com/audio2videoAI/internal/ai/replicate"
+	"github.com/audio2videoAI/internal/audio"
+	"github.com/audio2videoAI/internal/presets"
+	"github.com/audio2videoAI/internal/video"
+	"github.com/audio2videoAI/pkg/config"
+)
+
+func NewRunner(cfg config.Config) (*Runner, error) {
+	ell := elevenlabs.NewClient(cfg.ElevenLabsAPIKey, cfg.ElevenLabsBaseURL, cfg.ElevenLabsEnhancePath, cfg.HTTPTimeout)
+	replicateClient := replicate.NewClient(cfg.ReplicateAPIToken, cfg.ReplicateBaseURL, cfg.ReplicateModel, cfg.HTTPTimeout)
+	runner := &Runner{
+		ElevenLabs: ell,
+		Replicate:  replicateClient,
+		Transcribe: audio.TranscribeConfig{
+			Enabled:      cfg.TranscribeEnabled,
+			DockerPath:   cfg.WhisperDockerPath,
+			DockerImage:  cfg.WhisperDockerImage,
+			Model:        cfg.WhisperModel,
+			ModelDir:     cfg.WhisperModelDir,
+			AutoDownload: cfg.WhisperAutoDownload,
+		},
+		Thumbnails: video.ThumbnailConfig{
+			Enabled: cfg.ThumbnailsEnabled,
+			Format:  cfg.ThumbnailFormat,
+			Count:   cfg.ThumbnailCount,
+		},
+		Preview: video.PreviewConfig{
+			Enabled: cfg.PreviewEnabled,
+			Width:   cfg.PreviewWidth,
+			FPS:     cfg.PreviewFPS,
+		},
+		TemplatesDir: cfg.PromptTemplatesDir,
+		FFmpegPath:   cfg.FFmpegPath,
+		V2VModel:     cfg.ReplicateV2VModel,
+		PollInterval: cfg.JobPollInterval,
+		PreferWait:   cfg.ReplicatePreferWait,
+		Queue:        NewQueue(cfg.JobConcurrency),
+		CostPerSec:   cfg.ReplicateCostPerSec,
+		ModelCosts:   cfg.ReplicateModelCosts,
+
+		PromptMaxChars:        cfg.ReplicatePromptChars,
+		StoryboardShotSeconds: cfg.StoryboardShotSeconds,
+		StoryboardMaxChars:    cfg.LLMPromptChars,
+	}
+	if cfg.StoryboardEnabled {
+		runner.LLM = llm.NewClient(cfg.LLMAPIKey, cfg.LLMBaseURL, cfg.LLMModel, cfg.HTTPTimeout)
+	}
+	catalog, err := presets.Load(cfg.PresetsPath)
+	runner.Presets = catalog
+	return runner, err
+}
@@ -0,0 +1,154 @@
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/audio2videoAI/internal/video"
+)
+
+const (
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+type Record struct {
+	Path            string            `json:"-"`
+	Status          string            `json:"status"`
+	Error           string            `json:"error"`
+	CreatedAt       time.Time         `json:"created_at"`
+	AudioPath       string            `json:"audio_path"`
+	Lyrics          string            `json:"lyrics"`
+	Preset          string            `json:"preset"`
+	StylePreset     string            `json:"style_preset"`
+	AspectRatio     string            `json:"aspect_ratio"`
+	DurationSeconds int               `json:"duration_seconds"`
+	VideoPath       string            `json:"video_path"`
+	ReframeStrategy string            `json:"reframe_strategy"`
+	Variants        map[string]string `json:"variants"`
+	Variations      []string          `json:"variations"`
+	Thumbnails      []string          `json:"thumbnails"`
+	Overlay         video.Overlay     `json:"overlay"`
+	Effects         []string          `json:"effects"`
+	NegativePrompt  string            `json:"negative_prompt"`
+	Seed            *int              `json:"seed"`
+	Params          map[string]any    `json:"params"`
+	Model           string            `json:"model"`
+	SourceVideo     string            `json:"source_video"`
+	ReferenceImage  string            `json:"reference_image"`
+	Subject         SubjectReference  `json:"subject"`
+	Storyboard      []Shot            `json:"storyboard"`
+	Prompts         []string          `json:"prompts"`
+	PredictSeconds  float64           `json:"predict_seconds"`
+	EstimatedCost   float64           `json:"estimated_cost"`
+	PreviewGIF      string            `json:"preview_gif"`
+	PreviewWebP     string            `json:"preview_webp"`
+	Transcript      string            `json:"transcript"`
+	TranscriptPath  string            `json:"transcript_path"`
+	AudioBPM        float64           `json:"audio_bpm"`
+	AudioMeanDB     float64           `json:"audio_mean_db"`
+	AudioMaxDB      float64           `json:"audio_max_db"`
+	AudioDuration   float64           `json:"audio_duration"`
+	AudioHookTime   float64           `json:"audio_hook_time"`
+	AudioBeats      int               `json:"audio_beats"`
+	AudioOnsets     int               `json:"audio_onsets"`
+}
+
+func LoadHistory(outputDir string) ([]Record, error) {
+	paths, err := filepath.Glob(filepath.Join(outputDir, "metadata-*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var records []Record
+	var errs []error
+	for _, path := range paths {
+		record, err := LoadRecord(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].CreatedAt.After(records[j].CreatedAt) })
+	return records, errors.Join(errs...)
+}
+
+func LoadRecord(path string) (Record, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Record{}, err
+	}
+	var record Record
+	if err := json.Unmarshal(content, &record); err != nil {
+		return Record{}, fmt.Errorf("metadata %s: %w", path, err)
+	}
+	record.Path = path
+	if record.Status == "" {
+		record.Status = StatusSucceeded
+	}
+	if record.CreatedAt.IsZero() {
+		if info, err := os.Stat(path); err == nil {
+			record.CreatedAt = info.ModTime()
+		}
+	}
+	return record, nil
+}
+
+func (record Record) Input(outputDir string) JobInput {
+	input := JobInput{
+		AudioPath:       record.AudioPath,
+		Lyrics:          record.Lyrics,
+		Preset:          record.Preset,
+		StylePreset:     record.StylePreset,
+		AspectRatio:     record.AspectRatio,
+		DurationSeconds: record.DurationSeconds,
+		OutputDir:       outputDir,
+		ReframeStrategy: record.ReframeStrategy,
+		Overlay:         record.Overlay,
+		Effects:         record.Effects,
+		NegativePrompt:  record.NegativePrompt,
+		Seed:            record.Seed,
+		Params:          record.Params,
+		Model:           record.Model,
+		SourceVideo:     record.SourceVideo,
+		ReferenceImage:  record.ReferenceImage,
+		Subject:         record.Subject,
+		Storyboard:      record.Storyboard,
+	}
+	for aspect := range record.Variants {
+		input.ReframeAspects = append(input.ReframeAspects, aspect)
+	}
+	sort.Strings(input.ReframeAspects)
+	if len(record.Variations) > 1 {
+		input.Variations = len(record.Variations)
+	}
+	return input
+}
+
+func (record Record) Files() []string {
+	files := []string{record.VideoPath, record.PreviewGIF, record.PreviewWebP, record.TranscriptPath}
+	for _, path := range record.Variants {
+		files = append(files, path)
+	}
+	files = append(files, record.Variations...)
+	files = append(files, record.Thumbnails...)
+	return files
+}
+
+func DeleteRecord(record Record) error {
+	var errs []error
+	for _, path := range append(record.Files(), record.Path) {
+		if path == "" {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,162 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/audio2videoAI/internal/ai/replicate"
+)
+
+var (
+	referenceImageKeys = []string{"first_frame_image", "image", "start_image", "input_image"}
+	subjectImageKeys   = []string{"subject_reference", "subject_image", "reference_images", "character_reference"}
+)
+
+func ParseParams(value string) (map[string]any, error) {
+	params := map[string]any{}
+	for _, pair := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == '\n' }) {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, raw, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid parameter %q, expected key=value", pair)
+		}
+		params[key] = parseParamValue(strings.TrimSpace(raw))
+	}
+	return params, nil
+}
+
+func FormatParams(params map[string]any) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", key, params[key]))
+	}
+	return strings.Join(pairs, ", ")
+}
+
+func parseParamValue(raw string) any {
+	if parsed, err := strconv.Atoi(raw); err == nil {
+		return parsed
+	}
+	if parsed, err := strconv.ParseFloat(raw, 64); err == nil && !math.IsInf(parsed, 0) && !math.IsNaN(parsed) {
+		return parsed
+	}
+	switch raw {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return strings.Trim(raw, `"'`)
+}
+
+func buildPredictionInput(input JobInput, prompt string, schema replicate.InputSchema) (map[string]any, error) {
+	predictionInput := map[string]any{"prompt": prompt}
+	defaults := map[string]any{
+		"prompt_optimizer": true,
+		"duration":         input.DurationSeconds,
+		"aspect_ratio":     input.AspectRatio,
+	}
+	for key, value := range defaults {
+		if (!schema.Known() || schema.Has(key)) && value != 0 && value != "" {
+			predictionInput[key] = value
+		}
+	}
+	if negative := strings.TrimSpace(input.NegativePrompt); negative != "" && (!schema.Known() || schema.Has("negative_prompt")) {
+		predictionInput["negative_prompt"] = negative
+	}
+	if input.Seed != nil && SupportsSeed(schema) {
+		predictionInput["seed"] = *input.Seed
+	}
+	if err := ValidateParams(input.Params, schema); err != nil {
+		return nil, err
+	}
+	for key, value := range input.Params {
+		predictionInput[key] = value
+	}
+	return predictionInput, nil
+}
+
+func ValidateParams(params map[string]any, schema replicate.InputSchema) error {
+	if !schema.Known() {
+		return nil
+	}
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !schema.Has(key) {
+			return fmt.Errorf("parameter %q is not an input of this model; accepted inputs: %s", key, strings.Join(schema.Keys(), ", "))
+		}
+	}
+	return nil
+}
+
+func (runner *Runner) mediaInputs(ctx context.Context, input JobInput, schema replicate.InputSchema) (map[string]any, error) {
+	media := map[string]any{}
+	if path := strings.TrimSpace(input.ReferenceImage); path != "" {
+		key := firstSupportedKey(schema, referenceImageKeys)
+		if key == "" {
+			return nil, fmt.Errorf("model %s does not accept a reference image", runner.Replicate.Model)
+		}
+		value, err := runner.Replicate.FileInput(ctx, path)
+		if err != nil {
+			return nil, fmt.Errorf("reference image: %w", err)
+		}
+		media[key] = value
+	}
+	if images := input.Subject.images(); len(images) > 0 && schema.Known() {
+		key := firstSupportedKey(schema, subjectImageKeys)
+		if key == "" {
+			return media, nil
+		}
+		var values []any
+		for _, path := range images {
+			value, err := runner.Replicate.FileInput(ctx, path)
+			if err != nil {
+				return nil, fmt.Errorf("subject reference: %w", err)
+			}
+			values = append(values, value)
+		}
+		if schema.Properties[key].Type == "array" {
+			media[key] = values
+		} else {
+			media[key] = values[0]
+		}
+	}
+	return media, nil
+}
+
+func SupportsSeed(schema replicate.InputSchema) bool {
+	return !schema.Known() || schema.Has("seed")
+}
+
+func SupportsSubjectReference(schema replicate.InputSchema) bool {
+	return schema.Known() && firstSupportedKey(schema, subjectImageKeys) != ""
+}
+
+func firstSupportedKey(schema replicate.InputSchema, keys []string) string {
+	if !schema.Known() {
+		return keys[0]
+	}
+	for _, key := range keys {
+		if schema.Has(key) {
+			return key
+		}
+	}
+	return ""
+}
@@ -0,0 +1,46 @@
+package jobs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    map[string]any
+		wantErr bool
+	}{
+		{name: "empty", value: "", want: map[string]any{}},
+		{name: "integers", value: "steps=1, seed=0", want: map[string]any{"steps": 1, "seed": 0}},
+		{name: "float", value: "guidance=7.5", want: map[string]any{"guidance": 7.5}},
+		{name: "bools", value: "loop=true,fast=false", want: map[string]any{"loop": true, "fast": false}},
+		{name: "bool-like strings", value: "mode=t,flag=F,upper=TRUE", want: map[string]any{"mode": "t", "flag": "F", "upper": "TRUE"}},
+		{name: "quoted string", value: `style="noir"`, want: map[string]any{"style": "noir"}},
+		{name: "quoted number stays string", value: `seed="42"`, want: map[string]any{"seed": "42"}},
+		{name: "newline separated", value: "a=1\nb=two", want: map[string]any{"a": 1, "b": "two"}},
+		{name: "empty value", value: "negative=", want: map[string]any{"negative": ""}},
+		{name: "infinity stays string", value: "a=inf,b=-Inf,c=infinity", want: map[string]any{"a": "inf", "b": "-Inf", "c": "infinity"}},
+		{name: "nan stays string", value: "guidance=nan,other=NaN", want: map[string]any{"guidance": "nan", "other": "NaN"}},
+		{name: "missing equals", value: "steps", wantErr: true},
+		{name: "missing key", value: "=5", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseParams(test.value)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("ParseParams(%q) = %v, want error", test.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseParams(%q) error: %v", test.value, err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ParseParams(%q) = %#v, want %#v", test.value, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package jobs
+
+import (
+	"strings"
+
+	"github.com/audio2videoAI/internal/presets"
+)
+
+func (runner *Runner) Catalog() presets.Catalog {
+	if len(runner.Presets.Styles) == 0 && len(runner.Presets.Outcomes) == 0 {
+		return presets.Default()
+	}
+	return runner.Presets
+}
+
+func (runner *Runner) ApplyPresets(input JobInput) JobInput {
+	catalog := runner.Catalog()
+	if style, ok := catalog.Style(input.StylePreset); ok && strings.TrimSpace(input.NegativePrompt) == "" {
+		input.NegativePrompt = style.NegativePrompt
+	}
+	outcome, ok := catalog.Outcome(input.Preset)
+	if !ok {
+		return input
+	}
+	if input.AspectRatio == "" {
+		input.AspectRatio = outcome.AspectRatio
+	}
+	if input.DurationSeconds <= 0 {
+		input.DurationSeconds = outcome.DurationSeconds
+	}
+	if input.Model == "" {
+		input.Model = outcome.Provider
+	}
+	if input.ReframeStrategy == "" {
+		input.ReframeStrategy = outcome.PostProcess.Reframe
+	}
+	if len(input.Effects) == 0 {
+		input.Effects = outcome.PostProcess.Effects
+	}
+	return input
+}
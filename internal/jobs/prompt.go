@@ -0,0 +1,189 @@
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/audio2videoAI/internal/audio"
+)
+
+const defaultPromptTemplate = `cinematic music video visuals
+{{- range presetNotes .Input.Preset}}, {{.}}{{end}}
+{{- with .Input.StylePreset}}, style {{.}}{{end}}
+{{- range styleNotes .Input.StylePreset}}, {{.}}{{end}}
+{{- with .Input.AspectRatio}}, aspect ratio {{.}}{{end}}
+{{- with trim .Input.Lyrics}}, lyrics: {{.}}{{end}}
+{{- with trim .Transcript}}, transcript: {{.}}{{end}}
+{{- range vibe .Analysis}}, {{.}}{{end}}
+{{- with .AudioSource}}, audio source {{.}}{{end}}`
+
+const promptTemplateExt = ".tmpl"
+
+type PromptData struct {
+	Input       JobInput
+	Analysis    audio.Analysis
+	Transcript  string
+	Sections    []audio.Section
+	KeyLines    []string
+	AudioSource string
+}
+
+func (runner *Runner) BuildPrompt(input JobInput, preparation Preparation) (string, error) {
+	prompt, _, err := runner.buildBudgetedPrompt(input, preparation)
+	return prompt, err
+}
+
+func (runner *Runner) buildBudgetedPrompt(input JobInput, preparation Preparation) (string, bool, error) {
+	tmpl, err := loadPromptTemplate(runner.TemplatesDir, input, runner.promptFuncs())
+	if err != nil {
+		return "", false, err
+	}
+	data := PromptData{
+		Input:      input,
+		Analysis:   preparation.Analysis,
+		Transcript: preparation.Transcript,
+		Sections:   preparation.Analysis.Sections,
+		KeyLines:   KeyLines(input.Lyrics+"\n"+preparation.Transcript, maxKeyLines),
+	}
+	if preparation.EnhancedPath != "" {
+		data.AudioSource = filepath.Base(preparation.EnhancedPath)
+	}
+
+	prompt, err := renderPrompt(tmpl, data)
+	if err != nil || runner.PromptMaxChars <= 0 || len(prompt) <= runner.PromptMaxChars {
+		return prompt, false, err
+	}
+
+	lyricLines := KeyLines(input.Lyrics, maxKeyLines)
+	transcriptLines := KeyLines(preparation.Transcript, maxKeyLines)
+	for len(lyricLines)+len(transcriptLines) > 0 {
+		data.Input.Lyrics = strings.Join(lyricLines, " / ")
+		data.Transcript = strings.Join(transcriptLines, " / ")
+		prompt, err = renderPrompt(tmpl, data)
+		if err != nil || len(prompt) <= runner.PromptMaxChars {
+			return prompt, true, err
+		}
+		if len(transcriptLines) >= len(lyricLines) {
+			transcriptLines = transcriptLines[:len(transcriptLines)-1]
+		} else {
+			lyricLines = lyricLines[:len(lyricLines)-1]
+		}
+	}
+
+	data.Input.Lyrics = ""
+	data.Transcript = ""
+	prompt, err = renderPrompt(tmpl, data)
+	if err != nil {
+		return "", false, err
+	}
+	prompt, _ = fitPrompt(prompt, runner.PromptMaxChars)
+	return prompt, true, nil
+}
+
+func renderPrompt(tmpl *template.Template, data PromptData) (string, error) {
+	var builder strings.Builder
+	if err := tmpl.Execute(&builder, data); err != nil {
+		return "", fmt.Errorf("prompt template %s: %w", tmpl.Name(), err)
+	}
+	return strings.TrimSpace(builder.String()), nil
+}
+
+func PromptTemplateNames(dir string) []string {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+promptTemplateExt))
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(paths))
+	for _, path := range paths {
+		names = append(names, strings.TrimSuffix(filepath.Base(path), promptTemplateExt))
+	}
+	return names
+}
+
+func loadPromptTemplate(dir string, input JobInput, funcs template.FuncMap) (*template.Template, error) {
+	candidates := []string{
+		strings.TrimSpace(input.PromptTemplate),
+		strings.ToLower(strings.TrimSpace(input.Preset)),
+		"default",
+	}
+	for index, name := range candidates {
+		if name == "" || dir == "" {
+			continue
+		}
+		path := filepath.Join(dir, name+promptTemplateExt)
+		content, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			if index == 0 {
+				return nil, fmt.Errorf("prompt template not found: %s", path)
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return parsePromptTemplate(name, string(content), funcs)
+	}
+	if name := strings.TrimSpace(input.PromptTemplate); name != "" && name != "default" {
+		return nil, fmt.Errorf("prompt template not found: %s", name)
+	}
+	return parsePromptTemplate("default", defaultPromptTemplate, funcs)
+}
+
+func parsePromptTemplate(name, content string, funcs template.FuncMap) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(funcs).Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("prompt template %s: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+func (runner *Runner) promptFuncs() template.FuncMap {
+	return template.FuncMap{
+		"presetNotes": runner.presetNotes,
+		"styleNotes":  runner.styleNotes,
+		"vibe":        vibeFromAnalysis,
+		"trim":        strings.TrimSpace,
+		"lower":       strings.ToLower,
+		"upper":       strings.ToUpper,
+		"join":        func(sep string, values []string) string { return strings.Join(values, sep) },
+		"truncate":    truncatePrompt,
+		"keyLines":    KeyLines,
+	}
+}
+
+func truncatePrompt(max int, value string) string {
+	runes := []rune(value)
+	if max <= 0 || len(runes) <= max {
+		return value
+	}
+	return string(runes[:max])
+}
+
+func (runner *Runner) presetNotes(preset string) []string {
+	outcome, _ := runner.Catalog().Outcome(preset)
+	return outcome.Prompt
+}
+
+func (runner *Runner) styleNotes(style string) []string {
+	found, _ := runner.Catalog().Style(style)
+	return found.Prompt
+}
+
+func vibeFromAnalysis(analysis audio.Analysis) []string {
+	var notes []string
+	if analysis.BPM >= 120 {
+		notes = append(notes, "fast paced", "dynamic cuts", "high energy")
+	} else if analysis.BPM > 0 && analysis.BPM <= 90 {
+		notes = append(notes, "slow motion", "smooth transitions", "ambient")
+	}
+	if analysis.MaxVolume >= -10 {
+		notes = append(notes, "intense", "vibrant colors", "high contrast")
+	} else if analysis.MeanVolume <= -25 && analysis.MeanVolume < 0 {
+		notes = append(notes, "minimalist", "soft lighting", "calm")
+	}
+	return notes
+}
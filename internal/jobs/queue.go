@@ -0,0 +1,71 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+)
+
+type Queue struct {
+	mu      sync.Mutex
+	limit   int
+	active  int
+	changed chan struct{}
+}
+
+func NewQueue(concurrency int) *Queue {
+	return &Queue{limit: max(concurrency, 1), changed: make(chan struct{})}
+}
+
+func (queue *Queue) Acquire(ctx context.Context) (func(), error) {
+	if queue == nil {
+		return func() {}, nil
+	}
+	for {
+		queue.mu.Lock()
+		if queue.active < queue.limit {
+			queue.active++
+			queue.mu.Unlock()
+			var once sync.Once
+			return func() { once.Do(queue.release) }, nil
+		}
+		changed := queue.changed
+		queue.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (queue *Queue) Resize(concurrency int) {
+	if queue == nil {
+		return
+	}
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	queue.limit = max(concurrency, 1)
+	queue.notify()
+}
+
+func (queue *Queue) Limit() int {
+	if queue == nil {
+		return 0
+	}
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	return queue.limit
+}
+
+func (queue *Queue) release() {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	queue.active--
+	queue.notify()
+}
+
+func (queue *Queue) notify() {
+	close(queue.changed)
+	queue.changed = make(chan struct{})
+}
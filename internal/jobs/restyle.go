@@ -0,0 +1,48 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/audio2videoAI/internal/ai/replicate"
+	"github.com/audio2videoAI/internal/audio"
+	"github.com/audio2videoAI/internal/video"
+)
+
+var sourceVideoKeys = []string{"video", "input_video", "source_video", "video_url"}
+
+func validateSourceVideo(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("source video: %w", err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("source video is a directory")
+	}
+	return nil
+}
+
+func AcceptsSourceVideo(schema replicate.InputSchema) bool {
+	return schema.Known() && firstSupportedKey(schema, sourceVideoKeys) != ""
+}
+
+func (runner *Runner) sourceVideoInput(ctx context.Context, input JobInput, analysis audio.Analysis, schema replicate.InputSchema) (string, any, error) {
+	key := firstSupportedKey(schema, sourceVideoKeys)
+	if key == "" {
+		return "", nil, fmt.Errorf("model %s does not accept a source video", runner.Replicate.Model)
+	}
+	window := float64(input.DurationSeconds)
+	if analysis.Duration > 0 && (window <= 0 || analysis.Duration < window) {
+		window = analysis.Duration
+	}
+	trimmedPath, err := video.Trim(ctx, runner.FFmpegPath, input.SourceVideo, input.OutputDir, 0, window)
+	if err != nil {
+		return "", nil, err
+	}
+	value, err := runner.Replicate.FileInput(ctx, trimmedPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("source video: %w", err)
+	}
+	return key, value, nil
+}
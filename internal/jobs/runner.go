@@ -13,11 +13,17 @@ import (
 	"time"
 
 	"github.com/audio2videoAI/internal/ai/elevenlabs"
+	"github.com/audio2videoAI/internal/ai/llm"
 	"github.com/audio2videoAI/internal/ai/replicate"
 	"github.com/audio2videoAI/internal/audio"
+	"github.com/audio2videoAI/internal/presets"
+	"github.com/audio2videoAI/internal/video"
 )
 
+const LogsStage = "logs"
+
 type Event struct {
+	Time           time.Time
 	Stage          string
 	Message        string
 	Progress       float64
@@ -33,120 +39,344 @@ type JobInput struct {
 	AspectRatio     string
 	DurationSeconds int
 	OutputDir       string
+	ReframeStrategy string
+	ReframeAspects  []string
+	Overlay         video.Overlay
+	Effects         []string
+	PromptTemplate  string
+	NegativePrompt  string
+	Seed            *int
+	Params          map[string]any
+	Storyboard      []Shot
+	Preparation     *Preparation
+	ReferenceImage  string
+	Subject         SubjectReference
+	Model           string
+	Variations      int
+	Variation       *Variation
+	Candidates      []Variation
+	SourceVideo     string
 }
 
 type Result struct {
-	JobID     string
-	VideoPath string
-	MetaPath  string
+	JobID      string
+	VideoPath  string
+	FinalPath  string
+	MetaPath   string
+	Variants   []video.Variant
+	Thumbnails []string
+	Preview    video.Preview
+	Variations []Variation
 }
 
 type Runner struct {
 	ElevenLabs   *elevenlabs.Client
 	Replicate    *replicate.Client
+	LLM          *llm.Client
 	Transcribe   audio.TranscribeConfig
+	Thumbnails   video.ThumbnailConfig
+	Preview      video.PreviewConfig
+	Presets      presets.Catalog
+	TemplatesDir string
 	FFmpegPath   string
+	V2VModel     string
 	PollInterval time.Duration
 	PreferWait   bool
+	Queue        *Queue
+	CostPerSec   float64
+	ModelCosts   map[string]float64
+
+	PromptMaxChars        int
+	StoryboardShotSeconds int
+	StoryboardMaxChars    int
 }
 
-func (runner *Runner) Run(ctx context.Context, input JobInput, events chan<- Event) (Result, error) {
-	send := func(stage, message string, progress float64) {
-		if events != nil {
-			events <- Event{Stage: stage, Message: message, Progress: progress}
-		}
-	}
+type renderedClip struct {
+	PredictionID string
+	Path         string
+	Prompt       string
+	Truncated    bool
+	PredictTime  float64
+}
 
-	sendTranscript := func(stage, message, transcript, transcriptPath string, progress float64) {
-		if events != nil {
-			events <- Event{
-				Stage:          stage,
-				Message:        message,
-				Progress:       progress,
-				Transcript:     transcript,
-				TranscriptPath: transcriptPath,
-			}
-		}
-	}
+type Preparation struct {
+	EnhancedPath   string
+	Transcript     string
+	TranscriptPath string
+	Analysis       audio.Analysis
+}
 
-	if runner.Replicate == nil {
-		return Result{}, fmt.Errorf("replicate client not configured")
-	}
+func (runner *Runner) Prepare(ctx context.Context, input JobInput, events chan<- Event) (Preparation, error) {
+	send := sender(events)
 
 	send("validate", "Validating audio", 0.05)
 	if err := audio.ValidateAudioPath(input.AudioPath); err != nil {
-		return Result{}, err
+		return Preparation{}, err
+	}
+	if input.SourceVideo != "" {
+		if err := validateSourceVideo(input.SourceVideo); err != nil {
+			return Preparation{}, err
+		}
 	}
 
 	send("enhance", "Enhancing audio", 0.2)
-	enhancedPath := input.AudioPath
+	preparation := Preparation{EnhancedPath: input.AudioPath}
 	if runner.ElevenLabs != nil {
 		var err error
-		enhancedPath, err = runner.ElevenLabs.EnhanceAudio(ctx, input.AudioPath, input.OutputDir)
+		preparation.EnhancedPath, err = runner.ElevenLabs.EnhanceAudio(ctx, input.AudioPath, input.OutputDir)
 		if err != nil {
-			return Result{}, err
+			return Preparation{}, err
 		}
 	}
 
-	var transcript string
-	var transcriptPath string
 	if runner.Transcribe.Enabled {
 		send("transcribe", "Transcribing audio", 0.3)
 		var err error
-		transcript, transcriptPath, err = audio.Transcribe(ctx, runner.Transcribe, input.AudioPath, input.OutputDir)
+		preparation.Transcript, preparation.TranscriptPath, err = audio.Transcribe(ctx, runner.Transcribe, input.AudioPath, input.OutputDir)
 		if err != nil {
-			return Result{}, err
+			return Preparation{}, err
+		}
+		if events != nil {
+			events <- Event{
+				Time:           time.Now(),
+				Stage:          "transcribe",
+				Message:        "Transcript ready",
+				Progress:       0.35,
+				Transcript:     preparation.Transcript,
+				TranscriptPath: preparation.TranscriptPath,
+			}
 		}
-		sendTranscript("transcribe", "Transcript ready", transcript, transcriptPath, 0.35)
 	}
 
 	send("analyze", "Analyzing audio", 0.36)
-	analysis, err := audio.Analyze(ctx, runner.FFmpegPath, input.AudioPath)
+	var err error
+	preparation.Analysis, err = audio.Analyze(ctx, runner.FFmpegPath, input.AudioPath)
 	if err != nil {
-		return Result{}, err
+		return Preparation{}, err
 	}
+	return preparation, nil
+}
 
-	send("submit", "Submitting to Replicate", 0.4)
-	prompt := buildPrompt(input, enhancedPath, transcript, analysis)
-	prediction, err := runner.Replicate.SubmitPrediction(ctx, replicate.PredictionRequest{
-		Input: map[string]any{
-			"prompt":           prompt,
-			"prompt_optimizer": true,
-			"duration":         input.DurationSeconds,
-			"aspect_ratio":     input.AspectRatio,
-		},
-	}, runner.PreferWait)
+func (runner *Runner) Run(ctx context.Context, input JobInput, events chan<- Event) (Result, error) {
+	result, err := runner.run(ctx, input, events)
+	if err != nil && input.OutputDir != "" {
+		_, _ = writeFailedMetadata(input, result, err)
+	}
+	return result, err
+}
+
+func (runner *Runner) run(ctx context.Context, input JobInput, events chan<- Event) (Result, error) {
+	send := sender(events)
+
+	runner, input, err := runner.forInput(ctx, input, send)
 	if err != nil {
 		return Result{}, err
 	}
-
-	prediction, err = runner.pollPrediction(ctx, prediction, send)
+	preparation, err := runner.preparation(ctx, input, events)
 	if err != nil {
 		return Result{}, err
 	}
+	transcript := preparation.Transcript
+	transcriptPath := preparation.TranscriptPath
+	analysis := preparation.Analysis
 
-	send("download", "Downloading video", 0.9)
-	videoURL := replicate.OutputURL(prediction.Output)
-	if videoURL == "" {
-		return Result{}, fmt.Errorf("replicate output url missing")
+	variations := input.Candidates
+	if input.Variation == nil {
+		variations, err = runner.renderVariations(ctx, input, preparation, send)
+		if err != nil {
+			return Result{}, err
+		}
+		input.Variation = &variations[0]
 	}
-	videoPath, err := downloadOutput(ctx, videoURL, input.OutputDir)
-	if err != nil {
-		return Result{}, err
+	if input.Variation.Seed != nil {
+		input.Seed = input.Variation.Seed
 	}
+	shots := input.Variation.Shots
+	clips := input.Variation.clips
+	videoPath := input.Variation.Path
+	rendered := Result{JobID: clips[0].PredictionID, VideoPath: videoPath, Variations: variations}
 
 	muxedPath, err := muxAudio(ctx, runner.FFmpegPath, videoPath, input.AudioPath, input.OutputDir)
 	if err != nil {
-		return Result{}, err
+		return rendered, err
 	}
 
-	metaPath, err := writeMetadata(input, prediction.ID, muxedPath, transcript, transcriptPath, analysis)
+	basePath := muxedPath
+	if len(input.Effects) > 0 {
+		send("effects", "Applying audio-reactive effects", 0.92)
+		basePath, err = video.ApplyEffects(ctx, runner.FFmpegPath, input.Effects, analysis, muxedPath, input.OutputDir)
+		if err != nil {
+			return rendered, err
+		}
+	}
+
+	finalPath := basePath
+	if !input.Overlay.Empty() {
+		send("overlay", "Applying branding overlay", 0.93)
+		finalPath, err = video.ApplyOverlay(ctx, runner.FFmpegPath, input.Overlay, basePath, input.OutputDir)
+		if err != nil {
+			return rendered, err
+		}
+	}
+
+	result := rendered
+	result.FinalPath = finalPath
+
+	if aspects := reframeAspects(input); len(aspects) > 0 {
+		send("reframe", fmt.Sprintf("Reframing to %s", strings.Join(aspects, ", ")), 0.95)
+		variants, err := video.ReframeAll(ctx, runner.FFmpegPath, basePath, input.OutputDir, input.ReframeStrategy, aspects)
+		if err != nil {
+			send("reframe", fmt.Sprintf("Reframing failed, continuing without those variants: %v", err), 0.95)
+		}
+		for index, variant := range variants {
+			overlaid, err := video.ApplyOverlay(ctx, runner.FFmpegPath, input.Overlay, variant.Path, input.OutputDir)
+			if err != nil {
+				send("reframe", fmt.Sprintf("Overlay failed for the %s variant, keeping it without branding: %v", variant.AspectRatio, err), 0.95)
+				continue
+			}
+			variants[index].Path = overlaid
+		}
+		result.Variants = variants
+	}
+
+	if runner.Thumbnails.Enabled {
+		send("thumbnails", "Extracting thumbnails", 0.97)
+		result.Thumbnails, err = video.ExtractThumbnails(ctx, runner.FFmpegPath, runner.Thumbnails, videoPath, input.OutputDir, clipHookTime(analysis, input.DurationSeconds))
+		if err != nil {
+			send("thumbnails", fmt.Sprintf("Thumbnail extraction failed, continuing without thumbnails: %v", err), 0.97)
+		}
+	}
+
+	if runner.Preview.Enabled {
+		send("preview", "Generating GIF/WebP preview", 0.98)
+		result.Preview, err = video.GeneratePreview(ctx, runner.FFmpegPath, runner.Preview, finalPath, input.OutputDir)
+		if err != nil {
+			send("preview", fmt.Sprintf("Preview generation failed, continuing without a preview: %v", err), 0.98)
+		}
+	}
+
+	result.MetaPath, err = writeMetadata(input, result, shots, clips, transcript, transcriptPath, analysis, runner.CostRate(input.Model))
 	if err != nil {
 		return Result{}, err
 	}
 
 	send("done", "Completed", 1.0)
-	return Result{JobID: prediction.ID, VideoPath: videoPath, MetaPath: metaPath}, nil
+	return result, nil
+}
+
+func (runner *Runner) forInput(ctx context.Context, input JobInput, send func(string, string, float64)) (*Runner, JobInput, error) {
+	if runner.Replicate == nil {
+		return nil, input, fmt.Errorf("replicate client not configured")
+	}
+	if input.SourceVideo != "" {
+		if input.Model != "" && input.Model != runner.V2VModel {
+			modelRunner, modelInput := runner.withModel(input)
+			if schema, err := modelRunner.Replicate.FetchInputSchema(ctx); err == nil && AcceptsSourceVideo(schema) {
+				return modelRunner, modelInput, nil
+			}
+			if runner.V2VModel == "" {
+				return nil, input, fmt.Errorf("model %s does not accept a source video and no video-to-video model is configured", input.Model)
+			}
+			send("validate", fmt.Sprintf("Model %s does not accept a source video; using %s", input.Model, runner.V2VModel), 0.05)
+		}
+		if runner.V2VModel == "" {
+			return nil, input, fmt.Errorf("video-to-video model not configured")
+		}
+		input.Model = runner.V2VModel
+	}
+	runner, input = runner.withModel(input)
+	return runner, input, nil
+}
+
+func (runner *Runner) withModel(input JobInput) (*Runner, JobInput) {
+	if input.Model != "" && input.Model != runner.Replicate.Model {
+		modelRunner := *runner
+		modelRunner.Replicate = runner.Replicate.ForModel(input.Model)
+		runner = &modelRunner
+	}
+	input.Model = runner.Replicate.Model
+	return runner, input
+}
+
+func (runner *Runner) preparation(ctx context.Context, input JobInput, events chan<- Event) (Preparation, error) {
+	if input.Preparation != nil {
+		return *input.Preparation, nil
+	}
+	return runner.Prepare(ctx, input, events)
+}
+
+func clipHookTime(analysis audio.Analysis, durationSeconds int) float64 {
+	if durationSeconds <= 0 {
+		return analysis.HookTime
+	}
+	hook, ok := analysis.HookWithin(float64(durationSeconds))
+	if !ok {
+		return -1
+	}
+	return hook
+}
+
+func reframeAspects(input JobInput) []string {
+	strategy := strings.ToLower(strings.TrimSpace(input.ReframeStrategy))
+	if strategy == "" || strategy == "none" {
+		return nil
+	}
+	candidates := input.ReframeAspects
+	if len(candidates) == 0 {
+		candidates = video.SocialAspects()
+	}
+	var aspects []string
+	for _, aspect := range candidates {
+		if aspect != input.AspectRatio {
+			aspects = append(aspects, aspect)
+		}
+	}
+	return aspects
+}
+
+func (runner *Runner) renderClip(ctx context.Context, input JobInput, shot Shot, schema replicate.InputSchema, media map[string]any, send func(string, string, float64)) (renderedClip, error) {
+	clipInput, prompt, truncated := input.Subject.apply(input, shot.Prompt, runner.PromptMaxChars)
+	clipInput.DurationSeconds = snapDuration(schema, shot.Duration)
+	predictionInput, err := buildPredictionInput(clipInput, prompt, schema)
+	if err != nil {
+		return renderedClip{}, fmt.Errorf("model %s: %w", runner.Replicate.Model, err)
+	}
+	for key, value := range media {
+		predictionInput[key] = value
+	}
+	if schema.Known() {
+		if err := schema.Validate(predictionInput); err != nil {
+			return renderedClip{}, fmt.Errorf("replicate input invalid for %s: %w", runner.Replicate.Model, err)
+		}
+	}
+	prediction, err := runner.Replicate.SubmitPrediction(ctx, replicate.PredictionRequest{Input: predictionInput}, runner.PreferWait)
+	if err != nil {
+		return renderedClip{}, err
+	}
+
+	prediction, err = runner.pollPrediction(ctx, prediction, send)
+	if err != nil {
+		return renderedClip{}, err
+	}
+
+	send("download", "Downloading video", 0.9)
+	videoURL := replicate.OutputURL(prediction.Output)
+	if videoURL == "" {
+		return renderedClip{}, fmt.Errorf("replicate output url missing")
+	}
+	videoPath, err := downloadOutput(ctx, videoURL, input.OutputDir)
+	if err != nil {
+		return renderedClip{}, err
+	}
+	return renderedClip{PredictionID: prediction.ID, Path: videoPath, Prompt: prompt, Truncated: truncated, PredictTime: prediction.Metrics.PredictTime}, nil
+}
+
+func sender(events chan<- Event) func(stage, message string, progress float64) {
+	return func(stage, message string, progress float64) {
+		if events != nil {
+			events <- Event{Time: time.Now(), Stage: stage, Message: message, Progress: progress}
+		}
+	}
 }
 
 func (runner *Runner) pollPrediction(ctx context.Context, prediction replicate.Prediction, send func(string, string, float64)) (replicate.Prediction, error) {
@@ -155,7 +385,16 @@ func (runner *Runner) pollPrediction(ctx context.Context, prediction replicate.P
 		pollInterval = 4 * time.Second
 	}
 
+	logged := 0
 	for {
+		if len(prediction.Logs) < logged {
+			logged = 0
+		}
+		if logs := strings.TrimSpace(prediction.Logs[logged:]); logs != "" {
+			send(LogsStage, fmt.Sprintf("[%s] %s", prediction.ID, logs), 0)
+		}
+		logged = len(prediction.Logs)
+
 		status := strings.ToLower(prediction.Status)
 		progress := 0.4
 		if status == "processing" || status == "running" {
@@ -167,7 +406,7 @@ func (runner *Runner) pollPrediction(ctx context.Context, prediction replicate.P
 		case "succeeded", "completed":
 			return prediction, nil
 		case "failed", "canceled":
-			return replicate.Prediction{}, fmt.Errorf("replicate job failed")
+			return replicate.Prediction{}, &replicate.PredictionError{ID: prediction.ID, Status: prediction.Status, Detail: prediction.Error, Logs: prediction.Logs}
 		case "starting", "processing", "running", "queued":
 			// continue polling
 		default:
@@ -249,80 +488,118 @@ func downloadOutput(ctx context.Context, url, outputDir string) (string, error)
 	return outputPath, nil
 }
 
-func buildPrompt(input JobInput, enhancedPath, transcript string, analysis audio.Analysis) string {
-	promptParts := []string{"cinematic music video visuals"}
-	promptParts = append(promptParts, presetNotes(input.Preset)...)
-	if input.StylePreset != "" {
-		promptParts = append(promptParts, fmt.Sprintf("style %s", input.StylePreset))
-	}
-	if input.AspectRatio != "" {
-		promptParts = append(promptParts, fmt.Sprintf("aspect ratio %s", input.AspectRatio))
-	}
-	if strings.TrimSpace(input.Lyrics) != "" {
-		promptParts = append(promptParts, fmt.Sprintf("lyrics: %s", strings.TrimSpace(input.Lyrics)))
-	}
-	if strings.TrimSpace(transcript) != "" {
-		promptParts = append(promptParts, fmt.Sprintf("transcript: %s", strings.TrimSpace(transcript)))
-	}
-	promptParts = append(promptParts, vibeFromAnalysis(analysis)...)
-	if enhancedPath != "" {
-		promptParts = append(promptParts, fmt.Sprintf("audio source %s", filepath.Base(enhancedPath)))
+func (runner *Runner) CostRate(model string) float64 {
+	if rate, ok := runner.ModelCosts[model]; ok {
+		return rate
 	}
-	return strings.Join(promptParts, ", ")
+	return runner.CostPerSec
 }
 
-func presetNotes(preset string) []string {
-	switch strings.ToLower(strings.TrimSpace(preset)) {
-	case "hook":
-		return []string{"hook moment", "fast impact", "center focus"}
-	case "canvas":
-		return []string{"seamless loop", "subtle motion", "ambient visuals"}
-	case "highlight":
-		return []string{"dramatic highlight", "cinematic focus", "story beat"}
-	default:
-		return nil
+func writeMetadata(input JobInput, result Result, shots []Shot, clips []renderedClip, transcript, transcriptPath string, analysis audio.Analysis, costRate float64) (string, error) {
+	if err := os.MkdirAll(input.OutputDir, 0o755); err != nil {
+		return "", err
 	}
-}
 
-func vibeFromAnalysis(analysis audio.Analysis) []string {
-	var notes []string
-	if analysis.BPM >= 120 {
-		notes = append(notes, "fast paced", "dynamic cuts", "high energy")
-	} else if analysis.BPM > 0 && analysis.BPM <= 90 {
-		notes = append(notes, "slow motion", "smooth transitions", "ambient")
+	variants := map[string]string{}
+	for _, variant := range result.Variants {
+		variants[variant.AspectRatio] = variant.Path
+	}
+
+	var variations []string
+	for _, variation := range result.Variations {
+		variations = append(variations, variation.Path)
+	}
+
+	var predictionIDs []string
+	var prompts []string
+	truncated := false
+	predictSeconds := 0.0
+	for _, clip := range clips {
+		predictionIDs = append(predictionIDs, clip.PredictionID)
+		prompts = append(prompts, clip.Prompt)
+		truncated = truncated || clip.Truncated
+		predictSeconds += clip.PredictTime
+	}
+
+	payload := inputMetadata(input)
+	for key, value := range map[string]any{
+		"status":           StatusSucceeded,
+		"job_id":           result.JobID,
+		"video_path":       result.FinalPath,
+		"variants":         variants,
+		"variations":       variations,
+		"thumbnails":       result.Thumbnails,
+		"storyboard":       shots,
+		"prediction_ids":   predictionIDs,
+		"prompts":          prompts,
+		"prompt_truncated": truncated,
+		"predict_seconds":  predictSeconds,
+		"estimated_cost":   predictSeconds * costRate,
+		"preview_gif":      result.Preview.GIFPath,
+		"preview_webp":     result.Preview.WebPPath,
+		"transcript":       transcript,
+		"transcript_path":  transcriptPath,
+		"audio_bpm":        analysis.BPM,
+		"audio_mean_db":    analysis.MeanVolume,
+		"audio_max_db":     analysis.MaxVolume,
+		"audio_duration":   analysis.Duration,
+		"audio_hook_time":  analysis.HookTime,
+		"audio_beats":      len(analysis.Beats),
+		"audio_onsets":     len(analysis.Onsets),
+	} {
+		payload[key] = value
 	}
-	if analysis.MaxVolume >= -10 {
-		notes = append(notes, "intense", "vibrant colors", "high contrast")
-	} else if analysis.MeanVolume <= -25 && analysis.MeanVolume < 0 {
-		notes = append(notes, "minimalist", "soft lighting", "calm")
+	return saveMetadata(input.OutputDir, payload)
+}
+
+func RecordFailure(input JobInput, failure error) {
+	if input.OutputDir != "" {
+		_, _ = writeFailedMetadata(input, Result{}, failure)
 	}
-	return notes
 }
 
-func writeMetadata(input JobInput, jobID, videoPath, transcript, transcriptPath string, analysis audio.Analysis) (string, error) {
+func writeFailedMetadata(input JobInput, result Result, failure error) (string, error) {
 	if err := os.MkdirAll(input.OutputDir, 0o755); err != nil {
 		return "", err
 	}
+	payload := inputMetadata(input)
+	payload["status"] = StatusFailed
+	payload["error"] = failure.Error()
+	payload["storyboard"] = input.Storyboard
+	if result.VideoPath != "" {
+		payload["job_id"] = result.JobID
+		payload["video_path"] = result.VideoPath
+	}
+	if result.FinalPath != "" {
+		payload["video_path"] = result.FinalPath
+	}
+	return saveMetadata(input.OutputDir, payload)
+}
 
-	payload := map[string]any{
-		"job_id":           jobID,
+func inputMetadata(input JobInput) map[string]any {
+	return map[string]any{
 		"audio_path":       input.AudioPath,
 		"lyrics":           input.Lyrics,
 		"preset":           input.Preset,
 		"style_preset":     input.StylePreset,
 		"aspect_ratio":     input.AspectRatio,
 		"duration_seconds": input.DurationSeconds,
-		"video_path":       videoPath,
-		"transcript":       transcript,
-		"transcript_path":  transcriptPath,
-		"audio_bpm":        analysis.BPM,
-		"audio_mean_db":    analysis.MeanVolume,
-		"audio_max_db":     analysis.MaxVolume,
-		"audio_duration":   analysis.Duration,
+		"reframe_strategy": input.ReframeStrategy,
+		"overlay":          input.Overlay,
+		"effects":          input.Effects,
+		"negative_prompt":  input.NegativePrompt,
+		"seed":             input.Seed,
+		"params":           input.Params,
+		"model":            input.Model,
+		"source_video":     input.SourceVideo,
+		"reference_image":  input.ReferenceImage,
+		"subject":          input.Subject,
 		"created_at":       time.Now().Format(time.RFC3339),
 	}
+}
 
-	metaPath := filepath.Join(input.OutputDir, fmt.Sprintf("metadata-%d.json", time.Now().UnixNano()))
+func saveMetadata(outputDir string, payload map[string]any) (string, error) {
+	metaPath := filepath.Join(outputDir, fmt.Sprintf("metadata-%d.json", time.Now().UnixNano()))
 	file, err := os.Create(metaPath)
 	if err != nil {
 		return "", err
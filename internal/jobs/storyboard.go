@@ -0,0 +1,199 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/audio2videoAI/internal/ai/llm"
+	"github.com/audio2videoAI/internal/ai/replicate"
+)
+
+type Shot struct {
+	Duration int    `json:"duration"`
+	Prompt   string `json:"prompt"`
+}
+
+const storyboardSystemPrompt = `You are a music video director. Write a shot list for a short AI-generated music video.
+Reply with only a JSON array. Each item has "duration" (whole seconds) and "prompt" (one vivid visual description that includes the style).
+Keep the same characters, palette and setting across shots so the video stays coherent.`
+
+func (runner *Runner) GenerateStoryboard(ctx context.Context, input JobInput, preparation Preparation) ([]Shot, error) {
+	if runner.LLM == nil {
+		return nil, fmt.Errorf("storyboard llm not configured")
+	}
+	shotSeconds := runner.StoryboardShotSeconds
+	if shotSeconds <= 0 {
+		shotSeconds = 6
+	}
+	shots := int(math.Ceil(float64(input.DurationSeconds) / float64(shotSeconds)))
+	if shots < 1 {
+		shots = 1
+	}
+
+	var brief []string
+	brief = append(brief,
+		fmt.Sprintf("Total duration: %d seconds in about %d shots of %d seconds.", input.DurationSeconds, shots, shotSeconds),
+		fmt.Sprintf("Style: %s. Outcome: %s. Aspect ratio: %s.", input.StylePreset, input.Preset, input.AspectRatio),
+	)
+	var notes []string
+	notes = append(notes, runner.presetNotes(input.Preset)...)
+	notes = append(notes, runner.styleNotes(input.StylePreset)...)
+	if notes = append(notes, vibeFromAnalysis(preparation.Analysis)...); len(notes) > 0 {
+		brief = append(brief, "Mood: "+strings.Join(notes, ", ")+".")
+	}
+	if preparation.Analysis.BPM > 0 {
+		brief = append(brief, fmt.Sprintf("Tempo: %.0f BPM.", preparation.Analysis.BPM))
+	}
+	for _, section := range preparation.Analysis.Sections {
+		brief = append(brief, fmt.Sprintf("Section %s from %.0fs to %.0fs.", section.Label, section.Start, section.End))
+	}
+	lyrics := strings.TrimSpace(input.Lyrics)
+	transcript := strings.TrimSpace(preparation.Transcript)
+	if runner.StoryboardMaxChars > 0 && len(strings.Join(brief, "\n"))+len(lyrics)+len(transcript) > runner.StoryboardMaxChars {
+		lyrics = strings.Join(KeyLines(lyrics, maxKeyLines), "\n")
+		transcript = strings.Join(KeyLines(transcript, maxKeyLines), "\n")
+	}
+	if lyrics != "" {
+		brief = append(brief, "Lyrics:\n"+lyrics)
+	}
+	if transcript != "" {
+		brief = append(brief, "Transcript:\n"+transcript)
+	}
+	content, _ := fitPrompt(strings.Join(brief, "\n"), runner.StoryboardMaxChars)
+
+	reply, err := runner.LLM.Chat(ctx, []llm.Message{
+		{Role: "system", Content: storyboardSystemPrompt},
+		{Role: "user", Content: content},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseStoryboardReply(reply)
+}
+
+func FormatStoryboard(shots []Shot) string {
+	lines := make([]string, 0, len(shots))
+	for _, shot := range shots {
+		lines = append(lines, fmt.Sprintf("%d | %s", shot.Duration, shot.Prompt))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func ParseStoryboard(value string) ([]Shot, error) {
+	var shots []Shot
+	for number, line := range strings.Split(value, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		durationText, prompt, ok := strings.Cut(line, "|")
+		if !ok {
+			return nil, fmt.Errorf("storyboard line %d: expected \"<seconds> | <prompt>\"", number+1)
+		}
+		duration, err := strconv.Atoi(strings.TrimSpace(durationText))
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("storyboard line %d: invalid duration %q", number+1, strings.TrimSpace(durationText))
+		}
+		prompt = strings.TrimSpace(prompt)
+		if prompt == "" {
+			return nil, fmt.Errorf("storyboard line %d: prompt is empty", number+1)
+		}
+		shots = append(shots, Shot{Duration: duration, Prompt: prompt})
+	}
+	return shots, nil
+}
+
+func parseStoryboardReply(reply string) ([]Shot, error) {
+	start := strings.Index(reply, "[")
+	end := strings.LastIndex(reply, "]")
+	if start == -1 || end <= start {
+		return nil, fmt.Errorf("storyboard reply is not a JSON array: %s", strings.TrimSpace(reply))
+	}
+	var shots []Shot
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &shots); err != nil {
+		return nil, fmt.Errorf("storyboard reply invalid: %w", err)
+	}
+	var valid []Shot
+	for _, shot := range shots {
+		shot.Prompt = strings.TrimSpace(shot.Prompt)
+		if shot.Prompt == "" {
+			continue
+		}
+		if shot.Duration <= 0 {
+			shot.Duration = 1
+		}
+		valid = append(valid, shot)
+	}
+	if len(valid) == 0 {
+		return nil, fmt.Errorf("storyboard reply has no shots")
+	}
+	return valid, nil
+}
+
+func snapDuration(schema replicate.InputSchema, duration int) int {
+	values := schema.Enum("duration")
+	if len(values) == 0 {
+		return duration
+	}
+	best, bestDistance := duration, math.MaxInt
+	for _, value := range values {
+		option, err := strconv.Atoi(value)
+		if err != nil {
+			continue
+		}
+		distance := option - duration
+		if distance < 0 {
+			distance = -distance
+		}
+		if distance < bestDistance {
+			best, bestDistance = option, distance
+		}
+	}
+	return best
+}
+
+func concatVideos(ctx context.Context, ffmpegPath string, videoPaths []string, outputDir string) (string, error) {
+	if ffmpegPath == "" {
+		ffmpegPath = "ffmpeg"
+	}
+	listPath := filepath.Join(outputDir, fmt.Sprintf("concat-%d.txt", time.Now().UnixNano()))
+	var list strings.Builder
+	for _, path := range videoPaths {
+		absolute, err := filepath.Abs(path)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&list, "file '%s'\n", strings.ReplaceAll(absolute, "'", `'\''`))
+	}
+	if err := os.WriteFile(listPath, []byte(list.String()), 0o644); err != nil {
+		return "", err
+	}
+	defer os.Remove(listPath)
+
+	outputPath := filepath.Join(outputDir, fmt.Sprintf("storyboard-%d.mp4", time.Now().UnixNano()))
+	cmd := exec.CommandContext(
+		ctx,
+		ffmpegPath,
+		"-y",
+		"-f", "concat",
+		"-safe", "0",
+		"-i", listPath,
+		"-c:v", "libx264",
+		"-pix_fmt", "yuv420p",
+		"-an",
+		outputPath,
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("ffmpeg concat failed: %s", strings.TrimSpace(string(output)))
+	}
+	return outputPath, nil
+}
@@ -0,0 +1,49 @@
+package jobs
+
+import "strings"
+
+type SubjectReference struct {
+	Images []string `json:"images"`
+	Prompt string   `json:"prompt"`
+	Seed   *int     `json:"seed"`
+}
+
+func ParseSubjectImages(value string) []string {
+	var images []string
+	for _, path := range strings.Split(value, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			images = append(images, path)
+		}
+	}
+	return images
+}
+
+func (subject SubjectReference) images() []string {
+	var images []string
+	for _, path := range subject.Images {
+		if path = strings.TrimSpace(path); path != "" {
+			images = append(images, path)
+		}
+	}
+	return images
+}
+
+func (subject SubjectReference) apply(input JobInput, prompt string, maxChars int) (JobInput, string, bool) {
+	if subject.Seed != nil {
+		input.Seed = subject.Seed
+	}
+	description := strings.TrimSpace(subject.Prompt)
+	if description == "" {
+		fitted, truncated := fitPrompt(prompt, maxChars)
+		return input, fitted, truncated
+	}
+	budget := maxChars
+	if budget > 0 {
+		budget -= len(description) + 2
+		if budget < 1 {
+			budget = 1
+		}
+	}
+	fitted, truncated := fitPrompt(prompt, budget)
+	return input, description + ", " + fitted, truncated
+}
@@ -0,0 +1,199 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/audio2videoAI/internal/ai/replicate"
+)
+
+const MaxVariations = 4
+
+type Variation struct {
+	Seed  *int
+	Path  string
+	Shots []Shot
+	clips []renderedClip
+}
+
+func ParseVariations(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 1, nil
+	}
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 1 || count > MaxVariations {
+		return 0, fmt.Errorf("variations must be between 1 and %d", MaxVariations)
+	}
+	return count, nil
+}
+
+func (runner *Runner) RenderVariations(ctx context.Context, input JobInput, events chan<- Event) ([]Variation, error) {
+	variations, err := runner.renderVariationsFor(ctx, input, events)
+	if err != nil {
+		RecordFailure(input, err)
+	}
+	return variations, err
+}
+
+func (runner *Runner) renderVariationsFor(ctx context.Context, input JobInput, events chan<- Event) ([]Variation, error) {
+	runner, input, err := runner.forInput(ctx, input, sender(events))
+	if err != nil {
+		return nil, err
+	}
+	preparation, err := runner.preparation(ctx, input, events)
+	if err != nil {
+		return nil, err
+	}
+	return runner.renderVariations(ctx, input, preparation, sender(events))
+}
+
+func (runner *Runner) renderVariations(ctx context.Context, input JobInput, preparation Preparation, send func(string, string, float64)) ([]Variation, error) {
+	shots := input.Storyboard
+	if input.SourceVideo != "" {
+		shots = nil
+	} else if len(shots) == 0 && runner.LLM != nil {
+		send("storyboard", "Writing storyboard", 0.38)
+		var err error
+		shots, err = runner.GenerateStoryboard(ctx, input, preparation)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	send("submit", "Submitting to Replicate", 0.4)
+	schema, err := runner.Replicate.FetchInputSchema(ctx)
+	if err != nil {
+		send("submit", fmt.Sprintf("Could not load the input schema for %s (%v); submitting without validation", runner.Replicate.Model, err), 0.4)
+		schema = replicate.InputSchema{}
+	}
+	if input.Variations > 1 && !SupportsSeed(schema) {
+		return nil, fmt.Errorf("model %s has no seed input, so variations would all be the same; render a single variation", runner.Replicate.Model)
+	}
+	media, err := runner.mediaInputs(ctx, input, schema)
+	if err != nil {
+		return nil, err
+	}
+	if input.SourceVideo != "" {
+		send("trim", "Trimming source video to the audio window", 0.4)
+		key, value, err := runner.sourceVideoInput(ctx, input, preparation.Analysis, schema)
+		if err != nil {
+			return nil, err
+		}
+		media[key] = value
+	}
+	if input.Seed != nil && !SupportsSeed(schema) {
+		send("submit", fmt.Sprintf("Model %s has no seed input; the seed is ignored", runner.Replicate.Model), 0.4)
+	}
+	if strings.TrimSpace(input.NegativePrompt) != "" && schema.Known() && !schema.Has("negative_prompt") {
+		send("submit", fmt.Sprintf("Model %s has no negative prompt input; it is ignored", runner.Replicate.Model), 0.4)
+	}
+	if len(input.Subject.images()) > 0 && !SupportsSubjectReference(schema) {
+		send("submit", fmt.Sprintf("Model %s has no subject reference input; using subject prompt and seed only", runner.Replicate.Model), 0.4)
+	}
+
+	promptTrimmed := false
+	if len(shots) == 0 {
+		prompt, truncated, err := runner.buildBudgetedPrompt(input, preparation)
+		if err != nil {
+			return nil, err
+		}
+		if truncated {
+			send("submit", fmt.Sprintf("Prompt trimmed to %d characters", len(prompt)), 0.4)
+		}
+		promptTrimmed = truncated
+		shots = []Shot{{Duration: input.DurationSeconds, Prompt: prompt}}
+	}
+
+	count := input.Variations
+	if count > MaxVariations {
+		return nil, fmt.Errorf("variations must be between 1 and %d", MaxVariations)
+	}
+	if count <= 1 {
+		variation, err := runner.renderVariation(ctx, input, shots, promptTrimmed, schema, media, send)
+		if err != nil {
+			return nil, err
+		}
+		return []Variation{variation}, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	variations := make([]Variation, count)
+	errs := make([]error, count)
+	var wg sync.WaitGroup
+	for index, seed := range variationSeeds(input, count) {
+		variationInput := input
+		variationInput.Seed = &seed
+		if variationInput.Subject.Seed != nil {
+			variationInput.Subject.Seed = &seed
+		}
+		variationSend := func(stage, message string, progress float64) {
+			send(stage, fmt.Sprintf("Variation %d/%d: %s", index+1, count, message), progress)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			variations[index], errs[index] = runner.renderVariation(ctx, variationInput, shots, promptTrimmed, schema, media, variationSend)
+			if errs[index] != nil {
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+	for index, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("variation %d: %w", index+1, err)
+		}
+	}
+	return variations, nil
+}
+
+func (runner *Runner) renderVariation(ctx context.Context, input JobInput, shots []Shot, promptTrimmed bool, schema replicate.InputSchema, media map[string]any, send func(string, string, float64)) (Variation, error) {
+	variation := Variation{Seed: input.Seed, Shots: shots}
+	if input.Subject.Seed != nil {
+		variation.Seed = input.Subject.Seed
+	}
+	var clipPaths []string
+	for index, shot := range shots {
+		if len(shots) > 1 {
+			send("submit", fmt.Sprintf("Rendering shot %d/%d", index+1, len(shots)), 0.4)
+		}
+		clip, err := runner.renderClip(ctx, input, shot, schema, media, send)
+		if err != nil {
+			return Variation{}, err
+		}
+		clip.Truncated = clip.Truncated || promptTrimmed
+		variation.clips = append(variation.clips, clip)
+		clipPaths = append(clipPaths, clip.Path)
+	}
+
+	variation.Path = clipPaths[0]
+	if len(clipPaths) > 1 {
+		send("download", "Joining storyboard shots", 0.9)
+		var err error
+		variation.Path, err = concatVideos(ctx, runner.FFmpegPath, clipPaths, input.OutputDir)
+		if err != nil {
+			return Variation{}, err
+		}
+	}
+	return variation, nil
+}
+
+func variationSeeds(input JobInput, count int) []int {
+	base := rand.Intn(1 << 30)
+	if input.Subject.Seed != nil {
+		base = *input.Subject.Seed
+	} else if input.Seed != nil {
+		base = *input.Seed
+	}
+	seeds := make([]int, count)
+	for index := range seeds {
+		seeds[index] = base + index
+	}
+	return seeds
+}
@@ -0,0 +1,161 @@
+package presets
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/audio2videoAI/internal/video"
+	"gopkg.in/yaml.v3"
+)
+
+type Style struct {
+	Name           string   `json:"name" yaml:"name"`
+	Prompt         []string `json:"prompt" yaml:"prompt"`
+	NegativePrompt string   `json:"negative_prompt" yaml:"negative_prompt"`
+}
+
+type Outcome struct {
+	Name            string      `json:"name" yaml:"name"`
+	Prompt          []string    `json:"prompt" yaml:"prompt"`
+	AspectRatio     string      `json:"aspect_ratio" yaml:"aspect_ratio"`
+	DurationSeconds int         `json:"duration_seconds" yaml:"duration_seconds"`
+	Provider        string      `json:"provider" yaml:"provider"`
+	PostProcess     PostProcess `json:"post_process" yaml:"post_process"`
+}
+
+type PostProcess struct {
+	Reframe string   `json:"reframe" yaml:"reframe"`
+	Effects []string `json:"effects" yaml:"effects"`
+}
+
+type Catalog struct {
+	Styles   []Style   `json:"styles" yaml:"styles"`
+	Outcomes []Outcome `json:"presets" yaml:"presets"`
+}
+
+func Default() Catalog {
+	return Catalog{
+		Styles: []Style{
+			{Name: "cinematic"},
+			{Name: "anime"},
+			{Name: "cyberpunk"},
+			{Name: "surreal"},
+			{Name: "minimalist"},
+		},
+		Outcomes: []Outcome{
+			{Name: "Hook", Prompt: []string{"hook moment", "fast impact", "center focus"}},
+			{Name: "Canvas", Prompt: []string{"seamless loop", "subtle motion", "ambient visuals"}},
+			{Name: "Highlight", Prompt: []string{"dramatic highlight", "cinematic focus", "story beat"}},
+		},
+	}
+}
+
+func Load(path string) (Catalog, error) {
+	if strings.TrimSpace(path) == "" {
+		return Default(), nil
+	}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Default(), nil
+	}
+	if err != nil {
+		return Default(), err
+	}
+	var catalog Catalog
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(content, &catalog)
+	} else {
+		err = yaml.Unmarshal(content, &catalog)
+	}
+	if err != nil {
+		return Default(), fmt.Errorf("presets %s: %w", path, err)
+	}
+	if err := catalog.validate(); err != nil {
+		return Default(), fmt.Errorf("presets %s: %w", path, err)
+	}
+	defaults := Default()
+	if len(catalog.Styles) == 0 {
+		catalog.Styles = defaults.Styles
+	}
+	if len(catalog.Outcomes) == 0 {
+		catalog.Outcomes = defaults.Outcomes
+	}
+	return catalog, nil
+}
+
+func (catalog Catalog) StyleNames() []string {
+	names := make([]string, 0, len(catalog.Styles))
+	for _, style := range catalog.Styles {
+		names = append(names, style.Name)
+	}
+	return names
+}
+
+func (catalog Catalog) OutcomeNames() []string {
+	names := make([]string, 0, len(catalog.Outcomes))
+	for _, outcome := range catalog.Outcomes {
+		names = append(names, outcome.Name)
+	}
+	return names
+}
+
+func (catalog Catalog) Style(name string) (Style, bool) {
+	for _, style := range catalog.Styles {
+		if strings.EqualFold(style.Name, strings.TrimSpace(name)) {
+			return style, true
+		}
+	}
+	return Style{}, false
+}
+
+func (catalog Catalog) Outcome(name string) (Outcome, bool) {
+	for _, outcome := range catalog.Outcomes {
+		if strings.EqualFold(outcome.Name, strings.TrimSpace(name)) {
+			return outcome, true
+		}
+	}
+	return Outcome{}, false
+}
+
+func (catalog Catalog) validate() error {
+	var errs []error
+	seen := map[string]bool{}
+	for index, style := range catalog.Styles {
+		name := strings.ToLower(strings.TrimSpace(style.Name))
+		if name == "" {
+			errs = append(errs, fmt.Errorf("style %d has no name", index+1))
+		} else if seen["style:"+name] {
+			errs = append(errs, fmt.Errorf("duplicate style %q", style.Name))
+		}
+		seen["style:"+name] = true
+	}
+	for index, outcome := range catalog.Outcomes {
+		name := strings.ToLower(strings.TrimSpace(outcome.Name))
+		if name == "" {
+			errs = append(errs, fmt.Errorf("preset %d has no name", index+1))
+		} else if seen["preset:"+name] {
+			errs = append(errs, fmt.Errorf("duplicate preset %q", outcome.Name))
+		}
+		seen["preset:"+name] = true
+		if outcome.DurationSeconds < 0 {
+			errs = append(errs, fmt.Errorf("preset %q has a negative duration", outcome.Name))
+		}
+		switch outcome.PostProcess.Reframe {
+		case "", video.ReframeBlur, video.ReframeCrop, video.ReframePad:
+		default:
+			errs = append(errs, fmt.Errorf("preset %q has unknown reframe %q", outcome.Name, outcome.PostProcess.Reframe))
+		}
+		for _, effect := range outcome.PostProcess.Effects {
+			switch effect {
+			case video.EffectZoom, video.EffectFlash, video.EffectBrightness:
+			default:
+				errs = append(errs, fmt.Errorf("preset %q has unknown effect %q", outcome.Name, effect))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"github.com/audio2videoAI/internal/jobs"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	doneNewJob = iota
+	doneRerun
+	doneRerunStyle
+	doneQueue
+	doneQuit
+)
+
+func doneOptions() []string {
+	return []string{"New job", "Rerun with same settings", "Rerun with a different style", "Job queue", "Quit"}
+}
+
+func (model Model) handleDoneKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	options := doneOptions()
+	switch msg.String() {
+	case "up", "k":
+		model.doneIdx = (model.doneIdx + len(options) - 1) % len(options)
+	case "down", "j":
+		model.doneIdx = (model.doneIdx + 1) % len(options)
+	case "ctrl+c":
+		return model, tea.Quit
+	case "enter":
+		switch model.doneIdx {
+		case doneNewJob:
+			return model.newWizard()
+		case doneRerun:
+			input := model.lastInput
+			input.Variation = nil
+			input.Candidates = nil
+			input.Preparation = model.cachedPreparation(input.AudioPath)
+			model.resetRun()
+			return model.enqueue(input)
+		case doneRerunStyle:
+			model.prefill(model.lastInput)
+			model.resetRun()
+			model.editing = true
+			model.goTo(stepStyle)
+		case doneQueue:
+			model.step = stepRunning
+		case doneQuit:
+			return model, tea.Quit
+		}
+	}
+	return model, nil
+}
+
+func (model *Model) resetRun() {
+	model.err = nil
+	model.result = nil
+	model.status = ""
+	model.variations = nil
+	model.variationIdx = 0
+	model.confirmIdx = 0
+	model.doneIdx = 0
+}
+
+func (model Model) cachedPreparation(audioPath string) *jobs.Preparation {
+	if model.preparation == nil || model.preparedFor != audioPath {
+		return nil
+	}
+	return model.preparation
+}
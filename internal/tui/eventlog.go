@@ -0,0 +1,183 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/audio2videoAI/internal/ai/replicate"
+	"github.com/audio2videoAI/internal/jobs"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const predictionLogLines = 10
+
+type stageDuration struct {
+	stage    string
+	duration time.Duration
+	running  bool
+}
+
+func stageDurations(events []jobs.Event) []stageDuration {
+	var stages []stageDuration
+	var started time.Time
+	for _, event := range events {
+		if event.Stage == jobs.LogsStage || event.Time.IsZero() {
+			continue
+		}
+		if len(stages) > 0 && stages[len(stages)-1].stage == event.Stage {
+			stages[len(stages)-1].duration = event.Time.Sub(started)
+			continue
+		}
+		if len(stages) > 0 {
+			stages[len(stages)-1].duration = event.Time.Sub(started)
+			stages[len(stages)-1].running = false
+		}
+		stages = append(stages, stageDuration{stage: event.Stage, running: true})
+		started = event.Time
+	}
+	return stages
+}
+
+func formatStages(stages []stageDuration) string {
+	parts := make([]string, 0, len(stages))
+	for _, stage := range stages {
+		if stage.stage == "done" {
+			continue
+		}
+		part := fmt.Sprintf("%s %.1fs", stage.stage, stage.duration.Seconds())
+		if stage.running {
+			part = stage.stage + " (running)"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " · ")
+}
+
+func eventLog(events []jobs.Event) []string {
+	var lines []string
+	var start, stageStart time.Time
+	var previous jobs.Event
+	repeats := 0
+	collapsible := false
+	for _, event := range events {
+		if start.IsZero() {
+			start = event.Time
+		}
+		stamp := fmt.Sprintf("%s +%5.1fs", event.Time.Local().Format("15:04:05"), event.Time.Sub(start).Seconds())
+		if event.Stage == jobs.LogsStage {
+			for _, line := range strings.Split(event.Message, "\n") {
+				lines = append(lines, subtle.Render(fmt.Sprintf("%s  │ %s", stamp, strings.TrimRight(line, "\r"))))
+			}
+			collapsible = false
+			continue
+		}
+		if collapsible && event.Stage == previous.Stage && event.Message == previous.Message {
+			repeats++
+			lines[len(lines)-1] = fmt.Sprintf("%s  %-10s %s (x%d)", stamp, event.Stage, event.Message, repeats+1)
+			continue
+		}
+		if event.Stage != previous.Stage {
+			if previous.Stage != "" {
+				lines = append(lines, statusStyle.Render(fmt.Sprintf("%s  %-10s took %.1fs", stamp, previous.Stage, event.Time.Sub(stageStart).Seconds())))
+			}
+			stageStart = event.Time
+		}
+		repeats = 0
+		collapsible = true
+		previous = event
+		lines = append(lines, fmt.Sprintf("%s  %-10s %s", stamp, event.Stage, event.Message))
+	}
+	return lines
+}
+
+func (model *Model) refreshLog() {
+	if model.queueIdx >= len(model.queue) {
+		model.logView.SetContent("")
+		model.logJob = 0
+		return
+	}
+	job := model.queue[model.queueIdx]
+	follow := model.logJob != job.id || model.logView.AtBottom()
+	lines := eventLog(job.events)
+	model.logView.Height = min(max(len(lines), 1), model.logHeight)
+	model.logView.SetContent(strings.Join(lines, "\n"))
+	model.logJob = job.id
+	if follow {
+		model.logView.GotoBottom()
+	}
+}
+
+func (model Model) scrollLog(msg tea.KeyMsg) (Model, bool) {
+	switch msg.String() {
+	case "pgup":
+		model.logView.ViewUp()
+	case "pgdown":
+		model.logView.ViewDown()
+	case "home":
+		model.logView.GotoTop()
+	case "end":
+		model.logView.GotoBottom()
+	default:
+		return model, false
+	}
+	return model, true
+}
+
+func errorDetail(err error) []string {
+	lines := []string{warningStyle.Render(err.Error())}
+	var apiErr *replicate.APIError
+	var predictionErr *replicate.PredictionError
+	switch {
+	case errors.As(err, &apiErr):
+		lines = append(lines, "", fmt.Sprintf("Replicate %s request failed with HTTP %d", apiErr.Operation, apiErr.StatusCode))
+		if apiErr.Title != "" {
+			lines = append(lines, "Title:  "+apiErr.Title)
+		}
+		if apiErr.Detail != "" {
+			lines = append(lines, "Detail: "+apiErr.Detail)
+		}
+		for _, field := range apiErr.InvalidFields {
+			lines = append(lines, fmt.Sprintf("- %s: %s", field.Field, field.Description))
+		}
+		if apiErr.Body != "" {
+			lines = append(lines, "", subtle.Render("Response:"), apiErr.PrettyBody())
+		}
+	case errors.As(err, &predictionErr):
+		lines = append(lines, "", fmt.Sprintf("Prediction %s finished with status %s", predictionErr.ID, predictionErr.Status))
+		if predictionErr.Detail != nil {
+			lines = append(lines, "Error: "+prettyValue(predictionErr.Detail))
+		}
+		if logs := strings.TrimSpace(predictionErr.Logs); logs != "" {
+			logLines := strings.Split(logs, "\n")
+			if len(logLines) > predictionLogLines {
+				logLines = logLines[len(logLines)-predictionLogLines:]
+			}
+			lines = append(lines, "", subtle.Render(fmt.Sprintf("Last %d log lines:", len(logLines))))
+			lines = append(lines, logLines...)
+		}
+	default:
+		message := err.Error()
+		if index := strings.Index(message, "{"); index >= 0 {
+			var indented bytes.Buffer
+			if json.Indent(&indented, []byte(message[index:]), "", "  ") == nil {
+				lines = append(lines, "", subtle.Render("Details:"), indented.String())
+			}
+		}
+	}
+	return lines
+}
+
+func prettyValue(value any) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+	content, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(content)
+}
@@ -0,0 +1,262 @@
+package tui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/audio2videoAI/internal/audio"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	maxRecentDirs  = 5
+	browserVisible = 12
+)
+
+type browseEntry struct {
+	label string
+	path  string
+	dir   bool
+}
+
+type audioProbedMsg struct {
+	path string
+	info audio.Info
+	err  error
+}
+
+func recentDirsPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "a2v", "recent_dirs.json")
+}
+
+func loadRecentDirs() []string {
+	path := recentDirsPath()
+	if path == "" {
+		return nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var dirs []string
+	if err := json.Unmarshal(content, &dirs); err != nil {
+		return nil
+	}
+	return dirs
+}
+
+func saveRecentDirs(dirs []string) error {
+	path := recentDirsPath()
+	if path == "" {
+		return nil
+	}
+	content, err := json.Marshal(dirs)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0o644)
+}
+
+func (model *Model) rememberDir(dir string) {
+	dirs := []string{dir}
+	for _, recent := range model.recentDirs {
+		if recent != dir && len(dirs) < maxRecentDirs {
+			dirs = append(dirs, recent)
+		}
+	}
+	model.recentDirs = dirs
+	_ = saveRecentDirs(dirs)
+}
+
+func startDir(recentDirs []string) string {
+	for _, dir := range recentDirs {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return "."
+	}
+	return dir
+}
+
+func (model *Model) loadBrowser(dir string) {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		model.browseErr = err
+		return
+	}
+	model.browseDir = dir
+	model.browseErr = nil
+	model.browseIdx = 0
+
+	var listed []browseEntry
+	if parent := filepath.Dir(dir); parent != dir {
+		listed = append(listed, browseEntry{label: "..", path: parent, dir: true})
+	}
+	for _, recent := range model.recentDirs {
+		if recent != dir {
+			listed = append(listed, browseEntry{label: "recent: " + recent, path: recent, dir: true})
+		}
+	}
+	var dirs, files []browseEntry
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if entry.IsDir() {
+			dirs = append(dirs, browseEntry{label: entry.Name() + "/", path: path, dir: true})
+		} else if audio.IsAudioFile(entry.Name()) {
+			files = append(files, browseEntry{label: entry.Name(), path: path})
+		}
+	}
+	sort.Slice(dirs, func(i, j int) bool { return strings.ToLower(dirs[i].label) < strings.ToLower(dirs[j].label) })
+	sort.Slice(files, func(i, j int) bool { return strings.ToLower(files[i].label) < strings.ToLower(files[j].label) })
+	model.browseEntries = append(append(listed, dirs...), files...)
+	for index, entry := range model.browseEntries {
+		if !entry.dir && entry.path == model.audioPath {
+			model.browseIdx = index
+		}
+	}
+}
+
+func (model Model) handleAudioPathKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if model.pathTyping {
+		switch msg.String() {
+		case "tab":
+			model.pathTyping = false
+			model.audioPathInput.Blur()
+			return model, nil
+		case "enter":
+			path := strings.TrimSpace(model.audioPathInput.Value())
+			if path == "" {
+				return model, nil
+			}
+			if info, err := os.Stat(path); err == nil && info.IsDir() {
+				model.loadBrowser(path)
+				model.pathTyping = false
+				model.audioPathInput.Blur()
+				return model, nil
+			}
+			return model.selectAudio(path)
+		}
+		var cmd tea.Cmd
+		model.audioPathInput, cmd = model.audioPathInput.Update(msg)
+		return model, cmd
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		if len(model.browseEntries) > 0 {
+			model.browseIdx = (model.browseIdx + len(model.browseEntries) - 1) % len(model.browseEntries)
+		}
+	case "down", "j":
+		if len(model.browseEntries) > 0 {
+			model.browseIdx = (model.browseIdx + 1) % len(model.browseEntries)
+		}
+	case "tab":
+		model.pathTyping = true
+		model.audioPathInput.Focus()
+	case "left", "h", "backspace":
+		model.loadBrowser(filepath.Dir(model.browseDir))
+	case "right", "l", "enter":
+		if len(model.browseEntries) == 0 {
+			return model, nil
+		}
+		entry := model.browseEntries[model.browseIdx]
+		if entry.dir {
+			model.loadBrowser(entry.path)
+			return model, nil
+		}
+		if msg.String() == "enter" {
+			return model.selectAudio(entry.path)
+		}
+	}
+	return model, nil
+}
+
+func (model Model) selectAudio(path string) (tea.Model, tea.Cmd) {
+	if path == model.probePath && model.probeErr == nil && !model.probing && model.probeInfo != nil {
+		model.audioPath = path
+		if abs, err := filepath.Abs(filepath.Dir(path)); err == nil {
+			model.rememberDir(abs)
+		}
+		model.pathTyping = false
+		model.audioPathInput.Blur()
+		model.step = stepLyrics
+		model.lyricsInput.Focus()
+		return model, nil
+	}
+	model.probing = true
+	model.probePath = path
+	model.probeInfo = nil
+	model.probeErr = nil
+	return model, probeAudioCmd(model.config.FFmpegPath, path)
+}
+
+func probeAudioCmd(ffmpegPath, path string) tea.Cmd {
+	return func() tea.Msg {
+		info, err := audio.Probe(context.Background(), ffmpegPath, path)
+		return audioProbedMsg{path: path, info: info, err: err}
+	}
+}
+
+func (model Model) viewAudioPath() string {
+	lines := []string{headerStyle.Render("Audio File"), "", subtle.Render(model.browseDir), ""}
+	if len(model.browseEntries) == 0 {
+		lines = append(lines, subtle.Render("No audio files or folders here"))
+	}
+	start := 0
+	if model.browseIdx >= browserVisible {
+		start = model.browseIdx - browserVisible + 1
+	}
+	for index := start; index < len(model.browseEntries) && index < start+browserVisible; index++ {
+		entry := model.browseEntries[index]
+		if index == model.browseIdx && !model.pathTyping {
+			lines = append(lines, "> "+highlight.Render(entry.label))
+		} else {
+			lines = append(lines, "  "+entry.label)
+		}
+	}
+	if model.browseErr != nil {
+		lines = append(lines, "", warningStyle.Render(model.browseErr.Error()))
+	}
+	if model.pathTyping {
+		lines = append(lines, "", "Audio file path:", model.audioPathInput.View())
+	}
+
+	if model.probePath != "" && (model.pathTyping || filepath.Dir(model.probePath) == model.browseDir) {
+		name := filepath.Base(model.probePath)
+		switch {
+		case model.probing:
+			lines = append(lines, "", fmt.Sprintf("%s Probing %s...", model.spinner.View(), name))
+		case model.probeErr != nil:
+			lines = append(lines, "", warningStyle.Render(fmt.Sprintf("%s: %s", name, model.probeErr)))
+		case model.probeInfo != nil:
+			lines = append(lines, "", statusStyle.Render(fmt.Sprintf("%s: %s", name, model.probeInfo)), subtle.Render("Press Enter again to use this file"))
+		}
+	}
+	if model.pathTyping {
+		lines = append(lines, "", subtle.Render("Enter to check the file, Tab to browse, Esc to go back"))
+	} else {
+		lines = append(lines, "", subtle.Render("↑/↓ to move, Enter to open or check a file, Backspace for the parent folder, Tab to type a path, Esc to go back"))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,273 @@
+package tui
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/audio2videoAI/internal/jobs"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const historyVisible = 10
+
+func historySorts() []string {
+	return []string{"newest", "oldest", "audio", "style", "cost"}
+}
+
+func (model *Model) openHistory() {
+	filter := textinput.New()
+	filter.Placeholder = "filter by audio, style, preset, status or model"
+	model.historyFilter = filter
+	model.historyFiltering = false
+	model.historyIdx = 0
+	model.historyConfirm = false
+	model.historyStatus = ""
+	model.loadHistory()
+	model.step = stepHistory
+}
+
+func (model *Model) loadHistory() {
+	model.historyRecords, model.historyErr = jobs.LoadHistory(model.config.OutputDir)
+	if model.historyIdx >= len(model.visibleHistory()) {
+		model.historyIdx = 0
+	}
+}
+
+func (model Model) visibleHistory() []jobs.Record {
+	query := strings.ToLower(strings.TrimSpace(model.historyFilter.Value()))
+	var records []jobs.Record
+	for _, record := range model.historyRecords {
+		fields := []string{filepath.Base(record.AudioPath), record.StylePreset, record.Preset, record.Status, record.Model}
+		if query == "" || strings.Contains(strings.ToLower(strings.Join(fields, " ")), query) {
+			records = append(records, record)
+		}
+	}
+	switch historySorts()[model.historySort] {
+	case "oldest":
+		sort.SliceStable(records, func(i, j int) bool { return records[i].CreatedAt.Before(records[j].CreatedAt) })
+	case "audio":
+		sort.SliceStable(records, func(i, j int) bool {
+			return strings.ToLower(filepath.Base(records[i].AudioPath)) < strings.ToLower(filepath.Base(records[j].AudioPath))
+		})
+	case "style":
+		sort.SliceStable(records, func(i, j int) bool { return records[i].StylePreset < records[j].StylePreset })
+	case "cost":
+		sort.SliceStable(records, func(i, j int) bool { return model.historyCost(records[i]) > model.historyCost(records[j]) })
+	}
+	return records
+}
+
+func (model Model) handleHistoryKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if model.historyFiltering {
+		switch msg.String() {
+		case "enter", "esc":
+			model.historyFiltering = false
+			model.historyFilter.Blur()
+			return model, nil
+		}
+		var cmd tea.Cmd
+		model.historyFilter, cmd = model.historyFilter.Update(msg)
+		model.historyIdx = 0
+		return model, cmd
+	}
+
+	records := model.visibleHistory()
+	if model.historyConfirm {
+		model.historyConfirm = false
+		if msg.String() != "y" || len(records) == 0 {
+			model.historyStatus = "Delete cancelled"
+			return model, nil
+		}
+		record := records[model.historyIdx]
+		if err := jobs.DeleteRecord(record); err != nil {
+			model.historyStatus = "Delete failed: " + err.Error()
+		} else {
+			model.historyStatus = "Deleted " + filepath.Base(record.Path)
+		}
+		model.loadHistory()
+		return model, nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		model.step = stepInputType
+	case "up", "k":
+		if len(records) > 0 {
+			model.historyIdx = (model.historyIdx + len(records) - 1) % len(records)
+		}
+	case "down", "j":
+		if len(records) > 0 {
+			model.historyIdx = (model.historyIdx + 1) % len(records)
+		}
+	case "/":
+		model.historyFiltering = true
+		model.historyFilter.Focus()
+	case "s":
+		model.historySort = (model.historySort + 1) % len(historySorts())
+		model.historyIdx = 0
+	case "o":
+		if len(records) > 0 {
+			dir := filepath.Dir(records[model.historyIdx].Path)
+			if err := openFolder(dir); err != nil {
+				model.historyStatus = "Open failed: " + err.Error()
+			} else {
+				model.historyStatus = "Opened " + dir
+			}
+		}
+	case "d":
+		if len(records) > 0 {
+			model.historyConfirm = true
+		}
+	case "r":
+		if len(records) == 0 {
+			return model, nil
+		}
+		input := records[model.historyIdx].Input(model.config.OutputDir)
+		input.Preparation = model.cachedPreparation(input.AudioPath)
+		model.prefill(input)
+		model.resetRun()
+		return model.enqueue(input)
+	}
+	return model, nil
+}
+
+func (model *Model) prefill(input jobs.JobInput) {
+	model.inputType = inputAudioFile
+	model.audioPath = input.AudioPath
+	model.lyrics = input.Lyrics
+	model.lyricsInput.SetValue(input.Lyrics)
+	for index, name := range model.presetOptions() {
+		if strings.EqualFold(name, input.Preset) {
+			model.presetIdx = index
+		}
+	}
+	for index, name := range model.styleOptions() {
+		if strings.EqualFold(name, input.StylePreset) {
+			model.styleIdx = index
+		}
+	}
+	model.selectAspect(input.AspectRatio)
+	if input.DurationSeconds > 0 {
+		model.durationInput.SetValue(strconv.Itoa(input.DurationSeconds))
+	}
+	model.negativeInput.SetValue(input.NegativePrompt)
+}
+
+func openFolder(dir string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", dir)
+	case "windows":
+		cmd = exec.Command("explorer", dir)
+	default:
+		cmd = exec.Command("xdg-open", dir)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
+
+func (model Model) historyCost(record jobs.Record) float64 {
+	if record.EstimatedCost > 0 {
+		return record.EstimatedCost
+	}
+	return record.PredictSeconds * model.runner.CostRate(record.Model)
+}
+
+func (model Model) viewHistoryCost(record jobs.Record) string {
+	cost := model.historyCost(record)
+	if cost <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("~$%.2f", cost)
+}
+
+func (model Model) viewHistory() string {
+	lines := []string{headerStyle.Render("History"), ""}
+	if model.historyFiltering || model.historyFilter.Value() != "" {
+		lines = append(lines, "Filter: "+model.historyFilter.View(), "")
+	}
+
+	records := model.visibleHistory()
+	if len(records) == 0 {
+		lines = append(lines, subtle.Render("No runs found in "+model.config.OutputDir))
+	} else {
+		lines = append(lines, subtle.Render(fmt.Sprintf("  %-16s %-24s %-12s %-10s %-9s %s", "Date", "Audio", "Style", "Preset", "Status", "Est. cost")))
+	}
+	start := 0
+	if model.historyIdx >= historyVisible {
+		start = model.historyIdx - historyVisible + 1
+	}
+	for index := start; index < len(records) && index < start+historyVisible; index++ {
+		record := records[index]
+		line := fmt.Sprintf("%-16s %-24s %-12s %-10s %-9s %s",
+			record.CreatedAt.Local().Format("2006-01-02 15:04"),
+			truncateText(filepath.Base(record.AudioPath), 24),
+			truncateText(record.StylePreset, 12),
+			truncateText(record.Preset, 10),
+			record.Status,
+			model.viewHistoryCost(record),
+		)
+		if index == model.historyIdx {
+			lines = append(lines, "> "+highlight.Render(line))
+		} else if record.Status == jobs.StatusFailed {
+			lines = append(lines, "  "+warningStyle.Render(line))
+		} else {
+			lines = append(lines, "  "+line)
+		}
+	}
+	if len(records) > 0 {
+		lines = append(lines, "", model.viewHistoryDetail(records[model.historyIdx]))
+	}
+
+	if model.historyErr != nil {
+		lines = append(lines, "", warningStyle.Render(model.historyErr.Error()))
+	}
+	if model.historyConfirm {
+		lines = append(lines, "", warningStyle.Render("Delete this run and its output files? (y/n)"))
+	} else if model.historyStatus != "" {
+		lines = append(lines, "", statusStyle.Render(model.historyStatus))
+	}
+	lines = append(lines, "", subtle.Render(fmt.Sprintf("Sort: %s · / filter · s sort · o open folder · r rerun · d delete · Esc back", historySorts()[model.historySort])))
+	return strings.Join(lines, "\n")
+}
+
+func (model Model) viewHistoryDetail(record jobs.Record) string {
+	lines := []string{subtle.Render("Details:")}
+	if record.VideoPath != "" {
+		lines = append(lines, "Video: "+record.VideoPath)
+	}
+	if record.Error != "" {
+		lines = append(lines, warningStyle.Render("Error: "+record.Error))
+	}
+	if record.Model != "" {
+		lines = append(lines, "Model: "+record.Model)
+	}
+	if record.PredictSeconds > 0 {
+		lines = append(lines, fmt.Sprintf("GPU time: %.1fs · estimated cost %s (predict time × per-second rate, not a Replicate invoice)", record.PredictSeconds, model.viewHistoryCost(record)))
+	}
+	for index, prompt := range record.Prompts {
+		if index == 3 {
+			lines = append(lines, fmt.Sprintf("Prompt: ... %d more", len(record.Prompts)-index))
+			break
+		}
+		lines = append(lines, "Prompt: "+truncateText(prompt, 160))
+	}
+	if record.Transcript != "" {
+		lines = append(lines, "Transcript: "+truncateText(strings.Join(strings.Fields(record.Transcript), " "), 160))
+	}
+	if record.AudioDuration > 0 {
+		lines = append(lines, fmt.Sprintf("Analysis: %.0f BPM · %.1fs · hook at %.1fs · mean %.1f dB · max %.1f dB · %d beats · %d onsets",
+			record.AudioBPM, record.AudioDuration, record.AudioHookTime, record.AudioMeanDB, record.AudioMaxDB, record.AudioBeats, record.AudioOnsets))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -3,16 +3,21 @@ package tui
 import (
 	"context"
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/audio2videoAI/internal/ai/replicate"
 	"github.com/audio2videoAI/internal/audio"
 	"github.com/audio2videoAI/internal/jobs"
+	"github.com/audio2videoAI/internal/video"
 	"github.com/audio2videoAI/pkg/config"
 	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -27,9 +32,19 @@ const (
 	stepPreset
 	stepStyle
 	stepAspect
+	stepReframe
+	stepOverlay
+	stepOverlayText
+	stepEffects
 	stepDuration
+	stepAdvanced
+	stepSubject
 	stepConfirm
+	stepStoryboard
+	stepVariations
 	stepRunning
+	stepSettings
+	stepHistory
 	stepDone
 )
 
@@ -41,16 +56,23 @@ const (
 )
 
 type jobStartedMsg struct {
+	id     int
 	events <-chan jobs.Event
 	done   <-chan jobFinishedMsg
 }
 
 type jobFinishedMsg struct {
-	result jobs.Result
-	err    error
+	id         int
+	result     jobs.Result
+	input      jobs.JobInput
+	variations []jobs.Variation
+	err        error
 }
 
-type jobProgressMsg jobs.Event
+type jobProgressMsg struct {
+	id    int
+	event jobs.Event
+}
 
 type recordStartedMsg struct {
 	recorder *audio.Recorder
@@ -64,41 +86,116 @@ type recordFinishedMsg struct {
 
 type recordTickMsg struct{}
 
+type storyboardReadyMsg struct {
+	preparation jobs.Preparation
+	shots       []jobs.Shot
+	err         error
+}
+
+type schemaMsg struct {
+	model  string
+	schema replicate.InputSchema
+	err    error
+}
+
 type Model struct {
 	config config.Config
 	runner *jobs.Runner
 
-	step             Step
-	inputType        inputType
-	inputTypeIdx     int
-	presetIdx        int
-	styleIdx         int
-	aspectIdx        int
-	audioPath        string
-	lyrics           string
-	status           string
-	err              error
-	result           *jobs.Result
-	transcript       string
-	transcriptPath   string
-	recording        bool
-	recorder         *audio.Recorder
-	recordingStart   time.Time
-	recordingElapsed int
-	jobRunning       bool
-	jobEvents        []jobs.Event
+	step           Step
+	inputType      inputType
+	inputTypeIdx   int
+	presetIdx      int
+	styleIdx       int
+	aspectIdx      int
+	reframeIdx     int
+	overlayIdx     int
+	overlayPresets []video.OverlayPreset
+	overlayErr     error
+	overlayFocus   int
+	effectsIdx     int
+	advancedFocus  int
+	advancedErr    error
+	subjectFocus   int
+	subjectErr     error
+	aspects        []string
+	provider       string
+	schema         *replicate.InputSchema
+	schemaErr      error
+	durationErr    error
+	preparation    *jobs.Preparation
+	storyboardErr  error
+	variations     []jobs.Variation
+	variationIdx   int
+	confirmIdx     int
+	editing        bool
+	doneIdx        int
+	lastInput      jobs.JobInput
+	queue          []queuedJob
+	nextJobID      int
+	queueIdx       int
+	viewJob        int
+	logJob         int
+	logHeight      int
+	preparedFor    string
+
+	settingsInputs   []textinput.Model
+	settingsFocus    int
+	settingsProblems []config.Problem
+	settingsStatus   string
+
+	browseDir     string
+	browseEntries []browseEntry
+	browseIdx     int
+	browseErr     error
+	recentDirs    []string
+	pathTyping    bool
+	probing       bool
+	probePath     string
+	probeInfo     *audio.Info
+	probeErr      error
+
+	historyRecords   []jobs.Record
+	historyIdx       int
+	historySort      int
+	historyFilter    textinput.Model
+	historyFiltering bool
+	historyConfirm   bool
+	historyStatus    string
+	historyErr       error
+
+	storyboardLoading bool
+	audioPath         string
+	lyrics            string
+	status            string
+	err               error
+	result            *jobs.Result
+	recording         bool
+	recorder          *audio.Recorder
+	recordingStart    time.Time
+	recordingElapsed  int
 
 	audioPathInput    textinput.Model
 	recordDeviceInput textinput.Model
 	recordDurationInp textinput.Model
 	durationInput     textinput.Model
+	overlayTitleInput textinput.Model
+	overlayArtistInp  textinput.Model
+	negativeInput     textinput.Model
+	seedInput         textinput.Model
+	paramsInput       textinput.Model
+	imageInput        textinput.Model
+	variationsInput   textinput.Model
+	sourceVideoInput  textinput.Model
+	subjectImagesInp  textinput.Model
+	subjectPromptInp  textinput.Model
+	subjectSeedInput  textinput.Model
 	lyricsInput       textarea.Model
+	storyboardInput   textarea.Model
 
 	progress progress.Model
 	spinner  spinner.Model
-
-	eventChan <-chan jobs.Event
-	doneChan  <-chan jobFinishedMsg
+	logView  viewport.Model
 }
 
 var (
@@ -113,7 +210,6 @@ var (
 func NewModel(cfg config.Config, runner *jobs.Runner) Model {
 	audioPathInput := textinput.New()
 	audioPathInput.Placeholder = "/path/to/audio.wav"
-	audioPathInput.Focus()
 
 	recordDeviceInput := textinput.New()
 	recordDeviceInput.Placeholder = cfg.RecordDevice
@@ -128,18 +224,60 @@ func NewModel(cfg config.Config, runner *jobs.Runner) Model {
 	durationInput.Placeholder = "30"
 	durationInput.SetValue("30")
 
+	overlayTitleInput := textinput.New()
+	overlayTitleInput.Placeholder = "Track title"
+
+	overlayArtistInput := textinput.New()
+	overlayArtistInput.Placeholder = "Artist name"
+
+	negativeInput := textinput.New()
+	negativeInput.Placeholder = "blurry, text, watermark"
+
+	seedInput := textinput.New()
+	seedInput.Placeholder = "random"
+
+	paramsInput := textinput.New()
+	paramsInput.Placeholder = "prompt_optimizer=false, key=value"
+
+	imageInput := textinput.New()
+	imageInput.Placeholder = "/path/to/cover.jpg"
+
+	variationsInput := textinput.New()
+	variationsInput.Placeholder = "1"
+
+	sourceVideoInput := textinput.New()
+	sourceVideoInput.Placeholder = "/path/to/performance.mp4"
+
+	subjectImagesInput := textinput.New()
+	subjectImagesInput.Placeholder = "/path/to/face.jpg, /path/to/outfit.jpg"
+
+	subjectPromptInput := textinput.New()
+	subjectPromptInput.Placeholder = "a woman with short silver hair in a red jacket"
+
+	subjectSeedInput := textinput.New()
+	subjectSeedInput.Placeholder = "same as advanced seed"
+
+	overlayPresets, overlayErr := video.LoadOverlayPresets(cfg.OverlayPresetsPath)
+
 	lyricsInput := textarea.New()
 	lyricsInput.Placeholder = "Optional lyrics (press Ctrl+S to continue)"
 	lyricsInput.ShowLineNumbers = false
 	lyricsInput.SetWidth(60)
 	lyricsInput.SetHeight(6)
 
-	progressBar := progress.New(progress.WithDefaultGradient())
+	storyboardInput := textarea.New()
+	storyboardInput.Placeholder = "6 | opening shot description"
+	storyboardInput.ShowLineNumbers = true
+	storyboardInput.CharLimit = 0
+	storyboardInput.SetWidth(90)
+	storyboardInput.SetHeight(10)
+
+	progressBar := progress.New(progress.WithDefaultGradient(), progress.WithWidth(30))
 
 	spinnerModel := spinner.New()
 	spinnerModel.Spinner = spinner.Dot
 
-	return Model{
+	model := Model{
 		config:            cfg,
 		runner:            runner,
 		step:              stepInputType,
@@ -147,18 +285,40 @@ func NewModel(cfg config.Config, runner *jobs.Runner) Model {
 		presetIdx:         0,
 		styleIdx:          0,
 		aspectIdx:         0,
+		reframeIdx:        0,
+		aspects:           aspectOptions(),
+		overlayIdx:        0,
+		overlayPresets:    overlayPresets,
+		overlayErr:        overlayErr,
 		audioPathInput:    audioPathInput,
 		recordDeviceInput: recordDeviceInput,
 		recordDurationInp: recordDurationInput,
 		durationInput:     durationInput,
+		overlayTitleInput: overlayTitleInput,
+		overlayArtistInp:  overlayArtistInput,
+		negativeInput:     negativeInput,
+		seedInput:         seedInput,
+		paramsInput:       paramsInput,
+		imageInput:        imageInput,
+		variationsInput:   variationsInput,
+		sourceVideoInput:  sourceVideoInput,
+		subjectImagesInp:  subjectImagesInput,
+		subjectPromptInp:  subjectPromptInput,
+		subjectSeedInput:  subjectSeedInput,
 		lyricsInput:       lyricsInput,
+		storyboardInput:   storyboardInput,
 		progress:          progressBar,
 		spinner:           spinnerModel,
+		logView:           viewport.New(100, 12),
+		logHeight:         12,
+		recentDirs:        loadRecentDirs(),
 	}
+	model.loadBrowser(startDir(model.recentDirs))
+	return model
 }
 
 func (model Model) Init() tea.Cmd {
-	return model.spinner.Tick
+	return tea.Batch(model.spinner.Tick, fetchSchemaCmd(model.runner, ""))
 }
 
 func (model Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -168,7 +328,7 @@ func (model Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		model.spinner, cmd = model.spinner.Update(msg)
 		return model, cmd
 	case tea.KeyMsg:
-		if msg.Type == tea.KeyCtrlC || msg.String() == "q" {
+		if msg.Type == tea.KeyCtrlC || (msg.String() == "q" && !model.textFocused()) {
 			return model, tea.Quit
 		}
 		return model.handleKey(msg)
@@ -189,6 +349,48 @@ func (model Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		model.recordingElapsed = 0
 		model.status = "Recording audio"
 		return model, tea.Batch(recordTickCmd(), recordWaitCmd(model.recorder))
+	case storyboardReadyMsg:
+		model.storyboardLoading = false
+		if msg.err != nil {
+			model.storyboardErr = msg.err
+			model.preparation = nil
+			return model, nil
+		}
+		model.preparation = &msg.preparation
+		model.preparedFor = model.audioPath
+		model.storyboardInput.SetValue(jobs.FormatStoryboard(msg.shots))
+		model.storyboardInput.Focus()
+		return model, nil
+	case audioProbedMsg:
+		if msg.path != model.probePath {
+			return model, nil
+		}
+		model.probing = false
+		model.probeErr = msg.err
+		if msg.err == nil {
+			model.probeInfo = &msg.info
+		}
+		return model, nil
+	case schemaMsg:
+		if msg.model != model.provider {
+			return model, nil
+		}
+		if msg.err != nil {
+			model.schemaErr = msg.err
+			return model, nil
+		}
+		model.schema = &msg.schema
+		model.schemaErr = nil
+		selected := model.aspects[model.aspectIdx]
+		model.aspects = schemaAspects(msg.schema)
+		outcome, _ := model.runner.Catalog().Outcome(model.presetOptions()[model.presetIdx])
+		if !model.selectAspect(outcome.AspectRatio) && !model.selectAspect(selected) {
+			model.aspectIdx = 0
+		}
+		if values := msg.schema.Enum("duration"); len(values) > 0 && msg.schema.ValidateValue("duration", parseDuration(model.durationInput.Value())) != nil {
+			model.durationInput.SetValue(values[0])
+		}
+		return model, nil
 	case recordTickMsg:
 		if model.recording {
 			model.recordingElapsed = int(time.Since(model.recordingStart).Seconds())
@@ -204,41 +406,24 @@ func (model Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return model, nil
 		}
 		model.audioPath = msg.path
-		model.step = stepLyrics
 		model.status = "Recording complete"
-		model.lyricsInput.Focus()
+		if model.editing {
+			model.editing = false
+			model.goTo(stepConfirm)
+			return model, nil
+		}
+		model.goTo(stepLyrics)
 		return model, nil
 	case jobStartedMsg:
-		model.eventChan = msg.events
-		model.doneChan = msg.done
-		model.jobRunning = true
-		return model, tea.Batch(listenEventCmd(model.eventChan), listenDoneCmd(model.doneChan))
+		return model.jobStarted(msg)
 	case jobProgressMsg:
-		model.jobEvents = append(model.jobEvents, jobs.Event(msg))
-		if len(model.jobEvents) > 6 {
-			model.jobEvents = model.jobEvents[len(model.jobEvents)-6:]
-		}
-		if msg.Transcript != "" {
-			model.transcript = msg.Transcript
-			model.transcriptPath = msg.TranscriptPath
-		}
-		model.status = msg.Message
-		model.progress.SetPercent(msg.Progress)
-		return model, listenEventCmd(model.eventChan)
+		return model.jobProgress(msg)
 	case jobFinishedMsg:
-		model.jobRunning = false
-		if msg.err != nil {
-			model.err = msg.err
-			model.status = "Job failed"
-			model.step = stepDone
-			return model, nil
-		}
-		model.result = &msg.result
-		model.status = "Video ready"
-		model.step = stepDone
-		return model, nil
+		return model.jobFinished(msg)
 	case tea.WindowSizeMsg:
-		model.progress.Width = msg.Width - 8
+		model.logView.Width = msg.Width - 4
+		model.logHeight = max(6, msg.Height/3)
+		model.refreshLog()
 		return model, nil
 	}
 
@@ -262,10 +447,30 @@ func (model Model) View() string {
 		view = model.viewStyle()
 	case stepAspect:
 		view = model.viewAspect()
+	case stepReframe:
+		view = model.viewReframe()
+	case stepOverlay:
+		view = model.viewOverlay()
+	case stepOverlayText:
+		view = model.viewOverlayText()
+	case stepEffects:
+		view = model.viewEffects()
 	case stepDuration:
 		view = model.viewDuration()
+	case stepAdvanced:
+		view = model.viewAdvanced()
+	case stepSubject:
+		view = model.viewSubject()
 	case stepConfirm:
 		view = model.viewConfirm()
+	case stepStoryboard:
+		view = model.viewStoryboard()
+	case stepSettings:
+		view = model.viewSettings()
+	case stepHistory:
+		view = model.viewHistory()
+	case stepVariations:
+		view = model.viewVariations()
 	case stepRunning:
 		view = model.viewRunning()
 	case stepDone:
@@ -273,11 +478,18 @@ func (model Model) View() string {
 	default:
 		view = ""
 	}
+	if panel := model.viewQueuePanel(); panel != "" {
+		view += "\n\n" + panel
+	}
 	return view + "\n\n" + quitHint.Render("Press q or Ctrl+C to quit")
 }
 
-func (model Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+func (model Model) handleStepKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch model.step {
+	case stepSettings:
+		return model.handleSettingsKey(msg)
+	case stepHistory:
+		return model.handleHistoryKey(msg)
 	case stepInputType:
 		switch msg.String() {
 		case "up", "k":
@@ -285,28 +497,22 @@ func (model Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		case "down", "j":
 			model.inputTypeIdx = (model.inputTypeIdx + 1) % len(inputOptions())
 		case "enter":
-			if model.inputTypeIdx == 0 {
+			switch model.inputTypeIdx {
+			case 0:
 				model.inputType = inputAudioFile
-				model.step = stepAudioPath
-				model.audioPathInput.Focus()
-			} else {
+				model.goTo(stepAudioPath)
+			case 1:
 				model.inputType = inputRecord
 				model.step = stepRecordSettings
 				model.recordDeviceInput.Focus()
+			case 2:
+				model.openSettings()
+			default:
+				model.openHistory()
 			}
 		}
 	case stepAudioPath:
-		var cmd tea.Cmd
-		model.audioPathInput, cmd = model.audioPathInput.Update(msg)
-		switch msg.String() {
-		case "enter":
-			model.audioPath = strings.TrimSpace(model.audioPathInput.Value())
-			if model.audioPath != "" {
-				model.step = stepLyrics
-				model.lyricsInput.Focus()
-			}
-		}
-		return model, cmd
+		return model.handleAudioPathKey(msg)
 	case stepRecordSettings:
 		if model.recording {
 			switch msg.String() {
@@ -342,27 +548,94 @@ func (model Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case stepPreset:
 		switch msg.String() {
 		case "up", "k":
-			model.presetIdx = (model.presetIdx + len(presetOptions()) - 1) % len(presetOptions())
+			model.presetIdx = (model.presetIdx + len(model.presetOptions()) - 1) % len(model.presetOptions())
 		case "down", "j":
-			model.presetIdx = (model.presetIdx + 1) % len(presetOptions())
+			model.presetIdx = (model.presetIdx + 1) % len(model.presetOptions())
 		case "enter":
 			model.step = stepStyle
+			return model, model.applyOutcome()
 		}
 	case stepStyle:
 		switch msg.String() {
 		case "up", "k":
-			model.styleIdx = (model.styleIdx + len(styleOptions()) - 1) % len(styleOptions())
+			model.styleIdx = (model.styleIdx + len(model.styleOptions()) - 1) % len(model.styleOptions())
 		case "down", "j":
-			model.styleIdx = (model.styleIdx + 1) % len(styleOptions())
+			model.styleIdx = (model.styleIdx + 1) % len(model.styleOptions())
 		case "enter":
+			if style, ok := model.runner.Catalog().Style(model.styleOptions()[model.styleIdx]); ok && strings.TrimSpace(model.negativeInput.Value()) == "" {
+				model.negativeInput.SetValue(style.NegativePrompt)
+			}
 			model.step = stepAspect
 		}
 	case stepAspect:
 		switch msg.String() {
 		case "up", "k":
-			model.aspectIdx = (model.aspectIdx + len(aspectOptions()) - 1) % len(aspectOptions())
+			model.aspectIdx = (model.aspectIdx + len(model.aspects) - 1) % len(model.aspects)
+		case "down", "j":
+			model.aspectIdx = (model.aspectIdx + 1) % len(model.aspects)
+		case "enter":
+			model.step = stepReframe
+		}
+	case stepReframe:
+		switch msg.String() {
+		case "up", "k":
+			model.reframeIdx = (model.reframeIdx + len(reframeOptions()) - 1) % len(reframeOptions())
+		case "down", "j":
+			model.reframeIdx = (model.reframeIdx + 1) % len(reframeOptions())
+		case "enter":
+			model.step = stepOverlay
+		}
+	case stepOverlay:
+		options := model.overlayOptions()
+		switch msg.String() {
+		case "up", "k":
+			model.overlayIdx = (model.overlayIdx + len(options) - 1) % len(options)
+		case "down", "j":
+			model.overlayIdx = (model.overlayIdx + 1) % len(options)
+		case "enter":
+			if model.overlayIdx == 0 {
+				model.step = stepEffects
+				return model, nil
+			}
+			preset := model.overlayPresets[model.overlayIdx-1]
+			model.overlayTitleInput.SetValue(preset.Title)
+			model.overlayArtistInp.SetValue(preset.Artist)
+			model.overlayFocus = 0
+			model.overlayTitleInput.Focus()
+			model.overlayArtistInp.Blur()
+			model.step = stepOverlayText
+		}
+	case stepOverlayText:
+		switch msg.String() {
+		case "tab", "shift+tab":
+			model.overlayFocus = 1 - model.overlayFocus
+			if model.overlayFocus == 0 {
+				model.overlayTitleInput.Focus()
+				model.overlayArtistInp.Blur()
+			} else {
+				model.overlayArtistInp.Focus()
+				model.overlayTitleInput.Blur()
+			}
+			return model, nil
+		case "enter":
+			model.overlayTitleInput.Blur()
+			model.overlayArtistInp.Blur()
+			model.step = stepEffects
+			return model, nil
+		}
+		var cmd tea.Cmd
+		if model.overlayFocus == 0 {
+			model.overlayTitleInput, cmd = model.overlayTitleInput.Update(msg)
+		} else {
+			model.overlayArtistInp, cmd = model.overlayArtistInp.Update(msg)
+		}
+		return model, cmd
+	case stepEffects:
+		switch msg.String() {
+		case "up", "k":
+			model.effectsIdx = (model.effectsIdx + len(effectsOptions()) - 1) % len(effectsOptions())
 		case "down", "j":
-			model.aspectIdx = (model.aspectIdx + 1) % len(aspectOptions())
+			model.effectsIdx = (model.effectsIdx + 1) % len(effectsOptions())
 		case "enter":
 			model.step = stepDuration
 			model.durationInput.Focus()
@@ -372,22 +645,149 @@ func (model Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		model.durationInput, cmd = model.durationInput.Update(msg)
 		switch msg.String() {
 		case "enter":
+			model.durationErr = model.validateDuration()
+			if model.durationErr != nil {
+				return model, cmd
+			}
+			model.step = stepAdvanced
+			model.advancedFocus = 0
+			model.focusAdvanced()
+		}
+		return model, cmd
+	case stepAdvanced:
+		switch msg.String() {
+		case "tab", "down":
+			model.advancedFocus = (model.advancedFocus + 1) % advancedFields
+			model.focusAdvanced()
+			return model, nil
+		case "shift+tab", "up":
+			model.advancedFocus = (model.advancedFocus + advancedFields - 1) % advancedFields
+			model.focusAdvanced()
+			return model, nil
+		case "enter":
+			if _, _, err := model.advancedValues(); err != nil {
+				model.advancedErr = err
+				return model, nil
+			}
+			model.advancedErr = nil
+			model.advancedFocus = -1
+			model.focusAdvanced()
+			model.step = stepSubject
+			model.subjectFocus = 0
+			model.focusSubject()
+			return model, nil
+		}
+		var cmd tea.Cmd
+		switch model.advancedFocus {
+		case 0:
+			model.negativeInput, cmd = model.negativeInput.Update(msg)
+		case 1:
+			model.seedInput, cmd = model.seedInput.Update(msg)
+		case 2:
+			model.paramsInput, cmd = model.paramsInput.Update(msg)
+		case 3:
+			model.imageInput, cmd = model.imageInput.Update(msg)
+		case 4:
+			model.variationsInput, cmd = model.variationsInput.Update(msg)
+		case 5:
+			model.sourceVideoInput, cmd = model.sourceVideoInput.Update(msg)
+		}
+		return model, cmd
+	case stepSubject:
+		switch msg.String() {
+		case "tab", "down":
+			model.subjectFocus = (model.subjectFocus + 1) % subjectFields
+			model.focusSubject()
+			return model, nil
+		case "shift+tab", "up":
+			model.subjectFocus = (model.subjectFocus + subjectFields - 1) % subjectFields
+			model.focusSubject()
+			return model, nil
+		case "enter":
+			if _, err := model.subjectValue(); err != nil {
+				model.subjectErr = err
+				return model, nil
+			}
+			model.subjectErr = nil
+			model.subjectFocus = -1
+			model.focusSubject()
 			model.step = stepConfirm
+			return model, nil
+		}
+		var cmd tea.Cmd
+		switch model.subjectFocus {
+		case 0:
+			model.subjectImagesInp, cmd = model.subjectImagesInp.Update(msg)
+		case 1:
+			model.subjectPromptInp, cmd = model.subjectPromptInp.Update(msg)
+		case 2:
+			model.subjectSeedInput, cmd = model.subjectSeedInput.Update(msg)
 		}
 		return model, cmd
 	case stepConfirm:
+		rows := len(model.confirmRows()) + 1
 		switch msg.String() {
+		case "up", "k":
+			model.confirmIdx = (model.confirmIdx + rows - 1) % rows
+		case "down", "j":
+			model.confirmIdx = (model.confirmIdx + 1) % rows
 		case "enter":
-			model.step = stepRunning
-			return model, model.startJobCmd()
-		case "esc":
-			model.step = stepDuration
+			if model.confirmIdx > 0 {
+				model.editRow(model.confirmRows()[model.confirmIdx-1])
+				return model, nil
+			}
+			input := model.jobInput()
+			if model.runner.LLM != nil && input.SourceVideo == "" {
+				model.step = stepStoryboard
+				model.storyboardLoading = true
+				model.storyboardErr = nil
+				return model, prepareStoryboardCmd(model.runner, input)
+			}
+			return model.enqueue(input)
+		}
+	case stepStoryboard:
+		if model.storyboardLoading {
+			return model, nil
 		}
-	case stepDone:
 		switch msg.String() {
-		case "q", "ctrl+c":
-			return model, tea.Quit
+		case "ctrl+s":
+			shots, err := jobs.ParseStoryboard(model.storyboardInput.Value())
+			if err == nil && len(shots) == 0 {
+				err = fmt.Errorf("storyboard is empty; add at least one \"<seconds> | <prompt>\" line")
+			}
+			if err != nil {
+				model.storyboardErr = err
+				return model, nil
+			}
+			input := model.jobInput()
+			input.Storyboard = shots
+			input.Preparation = model.preparation
+			model.storyboardInput.Blur()
+			return model.enqueue(input)
 		}
+		var cmd tea.Cmd
+		model.storyboardInput, cmd = model.storyboardInput.Update(msg)
+		return model, cmd
+	case stepVariations:
+		switch msg.String() {
+		case "up", "k":
+			model.variationIdx = (model.variationIdx + len(model.variations) - 1) % len(model.variations)
+		case "down", "j":
+			model.variationIdx = (model.variationIdx + 1) % len(model.variations)
+		case "enter":
+			job := model.job(model.viewJob)
+			if job == nil {
+				return model, nil
+			}
+			input := job.input
+			input.Variation = &model.variations[model.variationIdx]
+			input.Candidates = model.variations
+			return model.restartJob(job.id, input)
+		}
+	case stepRunning:
+		return model.handleQueueKey(msg)
+	case stepDone:
+		return model.handleDoneKey(msg)
 	}
 
 	return model, nil
@@ -397,10 +797,6 @@ func (model Model) viewInputType() string {
 	return renderSelect("Choose input type", inputOptions(), model.inputTypeIdx)
 }
 
-func (model Model) viewAudioPath() string {
-	return fmt.Sprintf("%s\n\nAudio file path:\n%s\n\n%s", headerStyle.Render("Audio File"), model.audioPathInput.View(), subtle.Render("Press Enter to continue"))
-}
-
 func (model Model) viewRecord() string {
 	if model.recording {
 		maxSeconds := model.recordMaxDuration()
@@ -420,92 +816,227 @@ func (model Model) viewLyrics() string {
 }
 
 func (model Model) viewPreset() string {
-	return renderSelect("Select outcome preset", presetOptions(), model.presetIdx)
+	return renderSelect("Select outcome preset", model.presetOptions(), model.presetIdx)
 }
 
 func (model Model) viewStyle() string {
-	return renderSelect("Select style preset", styleOptions(), model.styleIdx)
+	return renderSelect("Select style preset", model.styleOptions(), model.styleIdx)
 }
 
 func (model Model) viewAspect() string {
-	return renderSelect("Select aspect ratio", aspectOptions(), model.aspectIdx)
+	view := renderSelect("Select aspect ratio", model.aspects, model.aspectIdx)
+	if model.schemaErr != nil {
+		view += "\n\n" + warningStyle.Render("Could not load model schema: "+model.schemaErr.Error())
+	}
+	return view
 }
 
-func (model Model) viewDuration() string {
-	return fmt.Sprintf("%s\n\nDuration (seconds):\n%s\n\n%s", headerStyle.Render("Duration"), model.durationInput.View(), subtle.Render("Press Enter to continue"))
+func (model Model) viewReframe() string {
+	return renderSelect("Derive other aspect ratios", reframeOptions(), model.reframeIdx)
 }
 
-func (model Model) viewConfirm() string {
+func (model Model) viewOverlay() string {
+	view := renderSelect("Select branding overlay", model.overlayOptions(), model.overlayIdx)
+	if model.overlayErr != nil {
+		view += "\n\n" + warningStyle.Render(model.overlayErr.Error())
+	}
+	return view
+}
+
+func (model Model) viewOverlayText() string {
 	return fmt.Sprintf(
-		"%s\n\nAudio: %s\nPreset: %s\nStyle: %s\nAspect: %s\nDuration: %s\nLyrics: %s\n\n%s",
-		headerStyle.Render("Confirm"),
-		model.audioPath,
-		presetOptions()[model.presetIdx],
-		styleOptions()[model.styleIdx],
-		aspectOptions()[model.aspectIdx],
-		model.durationInput.Value(),
-		lyricsSummary(model.lyrics),
-		subtle.Render("Press Enter to start, Esc to edit"),
+		"%s\n\nTitle:\n%s\n\nArtist:\n%s\n\n%s",
+		headerStyle.Render("Title Card"),
+		model.overlayTitleInput.View(),
+		model.overlayArtistInp.View(),
+		subtle.Render("Tab to switch fields, Enter to continue"),
 	)
 }
 
-func (model Model) viewRunning() string {
-	lines := []string{headerStyle.Render("Generating video"), ""}
-	if model.status != "" {
-		lines = append(lines, statusStyle.Render(model.status))
-	}
-	lines = append(lines, model.progress.View())
-	if model.transcript != "" {
-		lines = append(lines, "", subtle.Render("Transcript preview:"), truncateText(model.transcript, 280))
-		if model.transcriptPath != "" {
-			lines = append(lines, subtle.Render("Saved: "+model.transcriptPath))
+func (model Model) viewEffects() string {
+	return renderSelect("Audio-reactive effects", effectsOptions(), model.effectsIdx)
+}
+
+func (model Model) viewDuration() string {
+	lines := []string{headerStyle.Render("Duration"), "", "Duration (seconds):", model.durationInput.View()}
+	if model.schema != nil {
+		if hint := model.schema.Describe("duration"); hint != "" {
+			lines = append(lines, subtle.Render("Supported: "+hint))
+		} else {
+			lines = append(lines, subtle.Render("This model picks its own duration"))
 		}
 	}
-	if len(model.jobEvents) > 0 {
-		lines = append(lines, "", subtle.Render("Recent events:"))
-		for _, event := range model.jobEvents {
-			lines = append(lines, fmt.Sprintf("- %s: %s", event.Stage, event.Message))
-		}
+	if model.durationErr != nil {
+		lines = append(lines, warningStyle.Render(model.durationErr.Error()))
 	}
+	lines = append(lines, "", subtle.Render("Press Enter to continue"))
 	return strings.Join(lines, "\n")
 }
 
+func (model Model) viewAdvanced() string {
+	view := fmt.Sprintf(
+		"%s\n\nNegative prompt:\n%s\n\nSeed:\n%s\n\nModel parameters:\n%s\n\nReference image (first frame):\n%s\n\nVariations (1-%d):\n%s\n\nSource video to restyle (video-to-video):\n%s",
+		headerStyle.Render("Advanced (optional)"),
+		model.negativeInput.View(),
+		model.seedInput.View(),
+		model.paramsInput.View(),
+		model.imageInput.View(),
+		maxVariations,
+		model.variationsInput.View(),
+		model.sourceVideoInput.View(),
+	)
+	if strings.TrimSpace(model.sourceVideoInput.Value()) != "" && model.runner.V2VModel != "" && (model.schema == nil || !jobs.AcceptsSourceVideo(*model.schema)) {
+		view += "\n" + subtle.Render("Restyling uses "+model.runner.V2VModel)
+	}
+	if model.advancedErr != nil {
+		view += "\n\n" + warningStyle.Render(model.advancedErr.Error())
+	}
+	return view + "\n\n" + subtle.Render("Tab to switch fields, Enter to continue")
+}
+
+func (model Model) viewSubject() string {
+	lines := []string{headerStyle.Render("Subject reference (optional)"), ""}
+	if model.schema != nil && !jobs.SupportsSubjectReference(*model.schema) {
+		lines = append(lines, subtle.Render("This model has no subject reference input; images will be skipped"), "")
+	}
+	lines = append(lines,
+		"Reference images (comma separated):",
+		model.subjectImagesInp.View(),
+		"",
+		"Subject description (added to every prompt):",
+		model.subjectPromptInp.View(),
+		"",
+		"Subject seed (used for every clip):",
+		model.subjectSeedInput.View(),
+	)
+	if model.subjectErr != nil {
+		lines = append(lines, "", warningStyle.Render(model.subjectErr.Error()))
+	}
+	lines = append(lines, "", subtle.Render("Tab to switch fields, Enter to continue"))
+	return strings.Join(lines, "\n")
+}
+
+func (model Model) viewStoryboard() string {
+	if model.storyboardLoading {
+		return fmt.Sprintf("%s\n\n%s Analyzing audio and writing storyboard...", headerStyle.Render("Storyboard"), model.spinner.View())
+	}
+	lines := []string{headerStyle.Render("Storyboard"), ""}
+	if model.preparation != nil {
+		lines = append(lines, subtle.Render("One shot per line: <seconds> | <prompt>"), model.storyboardInput.View())
+	}
+	if model.storyboardErr != nil {
+		lines = append(lines, "", warningStyle.Render(model.storyboardErr.Error()))
+	}
+	if model.preparation == nil {
+		lines = append(lines, "", subtle.Render("Esc to go back"))
+	} else {
+		lines = append(lines, "", subtle.Render("Ctrl+S to render, Esc to go back"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (model Model) viewVariations() string {
+	options := make([]string, 0, len(model.variations))
+	for index, variation := range model.variations {
+		option := fmt.Sprintf("Variation %d: %s", index+1, variation.Path)
+		if variation.Seed != nil {
+			option = fmt.Sprintf("Variation %d (seed %d): %s", index+1, *variation.Seed, variation.Path)
+		}
+		options = append(options, option)
+	}
+	return renderSelect("Pick the variation to export", options, model.variationIdx)
+}
+
 func (model Model) viewDone() string {
 	if model.err != nil {
-		return fmt.Sprintf("%s\n\n%s\n\n%s", headerStyle.Render("Error"), warningStyle.Render(model.err.Error()), subtle.Render("Press q to quit"))
+		lines := append([]string{headerStyle.Render("Error"), ""}, errorDetail(model.err)...)
+		lines = append(lines, "", subtle.Render("The full event log is on the job queue screen"), "", model.viewDoneOptions())
+		return strings.Join(lines, "\n")
 	}
 	if model.result == nil {
-		return fmt.Sprintf("%s\n\n%s", headerStyle.Render("Done"), subtle.Render("Press q to quit"))
+		return fmt.Sprintf("%s\n\n%s", headerStyle.Render("Done"), model.viewDoneOptions())
 	}
-	return fmt.Sprintf(
-		"%s\n\nVideo: %s\nMetadata: %s\n\n%s",
+	lines := []string{
 		headerStyle.Render("Done"),
-		model.result.VideoPath,
-		model.result.MetaPath,
-		subtle.Render("Press q to quit"),
-	)
+		"",
+		"Video: " + model.result.VideoPath,
+		"Metadata: " + model.result.MetaPath,
+	}
+	if len(model.result.Variants) > 0 {
+		lines = append(lines, "", subtle.Render("Variants:"))
+		for _, variant := range model.result.Variants {
+			lines = append(lines, fmt.Sprintf("- %s: %s", variant.AspectRatio, variant.Path))
+		}
+	}
+	if len(model.result.Thumbnails) > 0 {
+		lines = append(lines, "", subtle.Render("Thumbnails:"))
+		for _, thumbnail := range model.result.Thumbnails {
+			lines = append(lines, "- "+thumbnail)
+		}
+	}
+	if len(model.variations) > 1 {
+		lines = append(lines, "", subtle.Render("Variations:"))
+		for _, variation := range model.variations {
+			lines = append(lines, "- "+variation.Path)
+		}
+	}
+	if model.result.Preview.GIFPath != "" {
+		lines = append(lines, "", subtle.Render("Preview:"), "- "+model.result.Preview.GIFPath, "- "+model.result.Preview.WebPPath)
+	}
+	lines = append(lines, "", model.viewDoneOptions())
+	return strings.Join(lines, "\n")
+}
+
+func (model Model) viewDoneOptions() string {
+	lines := []string{}
+	for index, option := range doneOptions() {
+		if index == model.doneIdx {
+			lines = append(lines, "> "+highlight.Render(option))
+		} else {
+			lines = append(lines, "  "+option)
+		}
+	}
+	lines = append(lines, "", subtle.Render("Use ↑/↓ and Enter"))
+	return strings.Join(lines, "\n")
 }
 
-func (model Model) startJobCmd() tea.Cmd {
+func (model Model) jobInput() jobs.JobInput {
 	input := jobs.JobInput{
 		AudioPath:       model.audioPath,
 		Lyrics:          model.lyrics,
-		Preset:          presetOptions()[model.presetIdx],
-		StylePreset:     styleOptions()[model.styleIdx],
-		AspectRatio:     aspectOptions()[model.aspectIdx],
+		Preset:          model.presetOptions()[model.presetIdx],
+		StylePreset:     model.styleOptions()[model.styleIdx],
+		AspectRatio:     aspectValue(model.aspects[model.aspectIdx]),
 		DurationSeconds: parseDuration(model.durationInput.Value()),
 		OutputDir:       model.config.OutputDir,
+		ReframeStrategy: reframeStrategies()[model.reframeIdx],
+		Overlay:         model.selectedOverlay(),
+		Effects:         effectsSelections()[model.effectsIdx],
+		NegativePrompt:  strings.TrimSpace(model.negativeInput.Value()),
+		ReferenceImage:  strings.TrimSpace(model.imageInput.Value()),
+		Preparation:     model.cachedPreparation(model.audioPath),
+		Model:           model.provider,
+		Variations:      model.variationCount(),
+		SourceVideo:     strings.TrimSpace(model.sourceVideoInput.Value()),
 	}
+	input.Seed, input.Params, _ = model.advancedValues()
+	input.Subject, _ = model.subjectValue()
+	return input
+}
+
+func prepareStoryboardCmd(runner *jobs.Runner, input jobs.JobInput) tea.Cmd {
 	return func() tea.Msg {
-		events := make(chan jobs.Event)
-		done := make(chan jobFinishedMsg, 1)
 		ctx := context.Background()
-		go func() {
-			result, err := model.runner.Run(ctx, input, events)
-			close(events)
-			done <- jobFinishedMsg{result: result, err: err}
-		}()
-		return jobStartedMsg{events: events, done: done}
+		if input.Preparation != nil {
+			shots, err := runner.GenerateStoryboard(ctx, input, *input.Preparation)
+			return storyboardReadyMsg{preparation: *input.Preparation, shots: shots, err: err}
+		}
+		preparation, err := runner.Prepare(ctx, input, nil)
+		if err != nil {
+			return storyboardReadyMsg{err: err}
+		}
+		shots, err := runner.GenerateStoryboard(ctx, input, preparation)
+		return storyboardReadyMsg{preparation: preparation, shots: shots, err: err}
 	}
 }
 
@@ -546,13 +1077,13 @@ func recordTickCmd() tea.Cmd {
 	})
 }
 
-func listenEventCmd(events <-chan jobs.Event) tea.Cmd {
+func listenEventCmd(id int, events <-chan jobs.Event) tea.Cmd {
 	return func() tea.Msg {
 		event, ok := <-events
 		if !ok {
 			return nil
 		}
-		return jobProgressMsg(event)
+		return jobProgressMsg{id: id, event: event}
 	}
 }
 
@@ -562,6 +1093,200 @@ func listenDoneCmd(done <-chan jobFinishedMsg) tea.Cmd {
 	}
 }
 
+func (model Model) overlayOptions() []string {
+	options := []string{"None"}
+	for _, preset := range model.overlayPresets {
+		options = append(options, preset.Name)
+	}
+	return options
+}
+
+func (model Model) selectedOverlay() video.Overlay {
+	if model.overlayIdx == 0 || model.overlayIdx > len(model.overlayPresets) {
+		return video.Overlay{}
+	}
+	overlay := model.overlayPresets[model.overlayIdx-1].Overlay
+	overlay.Title = strings.TrimSpace(model.overlayTitleInput.Value())
+	overlay.Artist = strings.TrimSpace(model.overlayArtistInp.Value())
+	return overlay
+}
+
+func (model *Model) focusAdvanced() {
+	inputs := []*textinput.Model{&model.negativeInput, &model.seedInput, &model.paramsInput, &model.imageInput, &model.variationsInput, &model.sourceVideoInput}
+	for index, input := range inputs {
+		if index == model.advancedFocus {
+			input.Focus()
+		} else {
+			input.Blur()
+		}
+	}
+}
+
+func (model Model) advancedValues() (*int, map[string]any, error) {
+	var seed *int
+	if value := strings.TrimSpace(model.seedInput.Value()); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("seed must be an integer")
+		}
+		seed = &parsed
+	}
+	params, err := jobs.ParseParams(model.paramsInput.Value())
+	if err != nil {
+		return nil, nil, err
+	}
+	if model.schema != nil {
+		if err := jobs.ValidateParams(params, *model.schema); err != nil {
+			return nil, nil, err
+		}
+	}
+	count, err := jobs.ParseVariations(model.variationsInput.Value())
+	if err != nil {
+		return nil, nil, err
+	}
+	if count > 1 && model.schema != nil && !jobs.SupportsSeed(*model.schema) {
+		return nil, nil, fmt.Errorf("this model has no seed input; variations need one")
+	}
+	if source := strings.TrimSpace(model.sourceVideoInput.Value()); source != "" {
+		info, err := os.Stat(source)
+		if err != nil {
+			return nil, nil, fmt.Errorf("source video: %w", err)
+		}
+		if info.IsDir() {
+			return nil, nil, fmt.Errorf("source video is a directory")
+		}
+		if model.runner.V2VModel == "" && (model.schema == nil || !jobs.AcceptsSourceVideo(*model.schema)) {
+			return nil, nil, fmt.Errorf("model %s does not accept a source video and no video-to-video model is configured", model.runner.Replicate.ForModel(model.provider).Model)
+		}
+	}
+	if image := strings.TrimSpace(model.imageInput.Value()); image != "" {
+		info, err := os.Stat(image)
+		if err != nil {
+			return nil, nil, fmt.Errorf("reference image: %w", err)
+		}
+		if info.IsDir() {
+			return nil, nil, fmt.Errorf("reference image is a directory")
+		}
+	}
+	return seed, params, nil
+}
+
+func (model *Model) focusSubject() {
+	inputs := []*textinput.Model{&model.subjectImagesInp, &model.subjectPromptInp, &model.subjectSeedInput}
+	for index, input := range inputs {
+		if index == model.subjectFocus {
+			input.Focus()
+		} else {
+			input.Blur()
+		}
+	}
+}
+
+func (model Model) subjectValue() (jobs.SubjectReference, error) {
+	subject := jobs.SubjectReference{
+		Images: jobs.ParseSubjectImages(model.subjectImagesInp.Value()),
+		Prompt: strings.TrimSpace(model.subjectPromptInp.Value()),
+	}
+	if value := strings.TrimSpace(model.subjectSeedInput.Value()); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return jobs.SubjectReference{}, fmt.Errorf("subject seed must be an integer")
+		}
+		subject.Seed = &parsed
+	}
+	for _, image := range subject.Images {
+		info, err := os.Stat(image)
+		if err != nil {
+			return jobs.SubjectReference{}, fmt.Errorf("subject image: %w", err)
+		}
+		if info.IsDir() {
+			return jobs.SubjectReference{}, fmt.Errorf("subject image %s is a directory", image)
+		}
+	}
+	return subject, nil
+}
+
+func (model Model) validateDuration() error {
+	if model.schema == nil || !model.schema.Has("duration") {
+		return nil
+	}
+	if err := model.schema.ValidateValue("duration", parseDuration(model.durationInput.Value())); err != nil {
+		return fmt.Errorf("duration %w", err)
+	}
+	return nil
+}
+
+func fetchSchemaCmd(runner *jobs.Runner, model string) tea.Cmd {
+	return func() tea.Msg {
+		if runner == nil || runner.Replicate == nil || runner.Replicate.APIToken == "" {
+			return nil
+		}
+		schema, err := runner.Replicate.ForModel(model).FetchInputSchema(context.Background())
+		return schemaMsg{model: model, schema: schema, err: err}
+	}
+}
+
+func (model *Model) applyOutcome() tea.Cmd {
+	outcome, ok := model.runner.Catalog().Outcome(model.presetOptions()[model.presetIdx])
+	if !ok {
+		return nil
+	}
+	model.selectAspect(outcome.AspectRatio)
+	if outcome.DurationSeconds > 0 {
+		model.durationInput.SetValue(strconv.Itoa(outcome.DurationSeconds))
+	}
+	for index, strategy := range reframeStrategies() {
+		if strategy == outcome.PostProcess.Reframe {
+			model.reframeIdx = index
+		}
+	}
+	for index, selection := range effectsSelections() {
+		if strings.Join(selection, ",") == strings.Join(outcome.PostProcess.Effects, ",") {
+			model.effectsIdx = index
+		}
+	}
+	provider := outcome.Provider
+	if provider == model.runner.Replicate.Model {
+		provider = ""
+	}
+	if provider == model.provider {
+		return nil
+	}
+	model.provider = provider
+	model.schema = nil
+	return fetchSchemaCmd(model.runner, provider)
+}
+
+func (model *Model) selectAspect(value string) bool {
+	if value == "" {
+		return false
+	}
+	for index, aspect := range model.aspects {
+		if aspect == value {
+			model.aspectIdx = index
+			return true
+		}
+	}
+	return false
+}
+
+func schemaAspects(schema replicate.InputSchema) []string {
+	if !schema.Has("aspect_ratio") {
+		return []string{modelDefaultAspect}
+	}
+	if values := schema.Enum("aspect_ratio"); len(values) > 0 {
+		return values
+	}
+	return aspectOptions()
+}
+
+func aspectValue(option string) string {
+	if option == modelDefaultAspect {
+		return ""
+	}
+	return option
+}
+
 func (model Model) recordMaxDuration() int {
 	value := parseDuration(model.recordDurationInp.Value())
 	if value <= 0 {
@@ -589,21 +1314,58 @@ func renderSelect(title string, options []string, selected int) string {
 }
 
 func inputOptions() []string {
-	return []string{"Use audio file", "Record audio"}
+	return []string{"Use audio file", "Record audio", "Settings", "History"}
 }
 
-func presetOptions() []string {
-	return []string{"Hook", "Canvas", "Highlight"}
+func (model Model) presetOptions() []string {
+	return model.runner.Catalog().OutcomeNames()
 }
 
-func styleOptions() []string {
-	return []string{"cinematic", "anime", "cyberpunk", "surreal", "minimalist"}
+func (model Model) styleOptions() []string {
+	return model.runner.Catalog().StyleNames()
 }
 
+const (
+	modelDefaultAspect = "Model default"
+	advancedFields     = 6
+	maxVariations      = jobs.MaxVariations
+	subjectFields      = 3
+)
+
 func aspectOptions() []string {
 	return []string{"9:16", "1:1"}
 }
 
+func reframeOptions() []string {
+	return []string{"None", "Blur background", "Crop", "Pad"}
+}
+
+func reframeStrategies() []string {
+	return []string{"", video.ReframeBlur, video.ReframeCrop, video.ReframePad}
+}
+
+func effectsOptions() []string {
+	return []string{"None", "Zoom pulses on beat", "Flash on kick", "Brightness follows volume", "All effects"}
+}
+
+func effectsSelections() [][]string {
+	return [][]string{
+		nil,
+		{video.EffectZoom},
+		{video.EffectFlash},
+		{video.EffectBrightness},
+		{video.EffectZoom, video.EffectFlash, video.EffectBrightness},
+	}
+}
+
+func (model Model) variationCount() int {
+	count, err := jobs.ParseVariations(model.variationsInput.Value())
+	if err != nil {
+		return 1
+	}
+	return count
+}
+
 func parseDuration(value string) int {
 	value = strings.TrimSpace(value)
 	if value == "" {
@@ -617,6 +1379,14 @@ func parseDuration(value string) int {
 	return parsed
 }
 
+func valueOrNone(value string) string {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return "(none)"
+	}
+	return truncateText(trimmed, 80)
+}
+
 func lyricsSummary(value string) string {
 	trimmed := strings.TrimSpace(value)
 	if trimmed == "" {
@@ -0,0 +1,234 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type confirmRow struct {
+	label string
+	value string
+	step  Step
+	focus int
+}
+
+func (model Model) confirmRows() []confirmRow {
+	audioStep := stepAudioPath
+	if model.inputType == inputRecord {
+		audioStep = stepRecordSettings
+	}
+	return []confirmRow{
+		{label: "Audio", value: model.audioPath, step: audioStep},
+		{label: "Lyrics", value: lyricsSummary(model.lyrics), step: stepLyrics},
+		{label: "Preset", value: model.presetOptions()[model.presetIdx], step: stepPreset},
+		{label: "Style", value: model.styleOptions()[model.styleIdx], step: stepStyle},
+		{label: "Aspect", value: model.aspects[model.aspectIdx], step: stepAspect},
+		{label: "Reframe", value: reframeOptions()[model.reframeIdx], step: stepReframe},
+		{label: "Overlay", value: model.overlayOptions()[model.overlayIdx], step: stepOverlay},
+		{label: "Effects", value: effectsOptions()[model.effectsIdx], step: stepEffects},
+		{label: "Duration", value: model.durationInput.Value(), step: stepDuration},
+		{label: "Negative", value: valueOrNone(model.negativeInput.Value()), step: stepAdvanced, focus: 0},
+		{label: "Seed", value: valueOrNone(model.seedInput.Value()), step: stepAdvanced, focus: 1},
+		{label: "Params", value: valueOrNone(model.paramsInput.Value()), step: stepAdvanced, focus: 2},
+		{label: "Image", value: valueOrNone(model.imageInput.Value()), step: stepAdvanced, focus: 3},
+		{label: "Variations", value: valueOrNone(model.variationsInput.Value()), step: stepAdvanced, focus: 4},
+		{label: "Source video", value: valueOrNone(model.sourceVideoInput.Value()), step: stepAdvanced, focus: 5},
+		{label: "Subject", value: valueOrNone(model.subjectPromptInp.Value()), step: stepSubject},
+	}
+}
+
+func (model Model) previousStep() (Step, bool) {
+	switch model.step {
+	case stepAudioPath, stepRecordSettings:
+		return stepInputType, true
+	case stepLyrics:
+		if model.inputType == inputRecord {
+			return stepRecordSettings, true
+		}
+		return stepAudioPath, true
+	case stepPreset:
+		return stepLyrics, true
+	case stepStyle:
+		return stepPreset, true
+	case stepAspect:
+		return stepStyle, true
+	case stepReframe:
+		return stepAspect, true
+	case stepOverlay:
+		return stepReframe, true
+	case stepOverlayText:
+		return stepOverlay, true
+	case stepEffects:
+		if model.overlayIdx > 0 {
+			return stepOverlayText, true
+		}
+		return stepOverlay, true
+	case stepDuration:
+		return stepEffects, true
+	case stepAdvanced:
+		return stepDuration, true
+	case stepSubject:
+		return stepAdvanced, true
+	case stepConfirm:
+		return stepSubject, true
+	case stepStoryboard:
+		return stepConfirm, !model.storyboardLoading
+	default:
+		return model.step, false
+	}
+}
+
+func (model Model) textFocused() bool {
+	var inputs []textinput.Model
+	switch model.step {
+	case stepSettings:
+		return true
+	case stepHistory:
+		return model.historyFiltering
+	case stepAudioPath:
+		return model.pathTyping
+	case stepRecordSettings:
+		return !model.recording
+	case stepLyrics:
+		return model.lyricsInput.Focused()
+	case stepStoryboard:
+		return model.storyboardInput.Focused()
+	case stepDuration:
+		inputs = []textinput.Model{model.durationInput}
+	case stepOverlayText:
+		inputs = []textinput.Model{model.overlayTitleInput, model.overlayArtistInp}
+	case stepAdvanced:
+		inputs = []textinput.Model{model.negativeInput, model.seedInput, model.paramsInput, model.imageInput, model.variationsInput, model.sourceVideoInput}
+	case stepSubject:
+		inputs = []textinput.Model{model.subjectImagesInp, model.subjectPromptInp, model.subjectSeedInput}
+	}
+	for _, input := range inputs {
+		if input.Focused() {
+			return true
+		}
+	}
+	return false
+}
+
+func (model Model) isBackKey(msg tea.KeyMsg) bool {
+	switch msg.Type {
+	case tea.KeyEsc:
+		return !model.recording
+	case tea.KeyBackspace:
+	default:
+		return false
+	}
+	switch model.step {
+	case stepAudioPath:
+		return model.pathTyping && model.audioPathInput.Value() == ""
+	case stepRecordSettings:
+		return !model.recording && model.recordDeviceInput.Value() == ""
+	case stepLyrics:
+		return model.lyricsInput.Value() == ""
+	case stepOverlayText:
+		return model.overlayTitleInput.Value() == "" && model.overlayArtistInp.Value() == ""
+	case stepDuration:
+		return model.durationInput.Value() == ""
+	case stepAdvanced, stepSubject, stepStoryboard:
+		return false
+	default:
+		return true
+	}
+}
+
+func (model Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if model.step != stepSettings && model.step != stepHistory && model.isBackKey(msg) {
+		if model.editing && model.step != stepConfirm {
+			model.editing = false
+			model.goTo(stepConfirm)
+			return model, nil
+		}
+		if previous, ok := model.previousStep(); ok {
+			model.goTo(previous)
+			return model, nil
+		}
+	}
+
+	from := model.step
+	updated, cmd := model.handleStepKey(msg)
+	next, ok := updated.(Model)
+	if !ok || !next.editing || next.step <= from {
+		return updated, cmd
+	}
+	switch next.step {
+	case stepAudioPath, stepRecordSettings, stepOverlayText:
+		return next, cmd
+	}
+	next.editing = false
+	next.goTo(stepConfirm)
+	return next, cmd
+}
+
+func (model *Model) editRow(row confirmRow) {
+	model.editing = true
+	model.goTo(row.step)
+	if row.step == stepAdvanced {
+		model.advancedFocus = row.focus
+		model.focusAdvanced()
+	}
+}
+
+func (model *Model) goTo(step Step) {
+	model.audioPathInput.Blur()
+	model.recordDeviceInput.Blur()
+	model.lyricsInput.Blur()
+	model.overlayTitleInput.Blur()
+	model.overlayArtistInp.Blur()
+	model.durationInput.Blur()
+	model.storyboardInput.Blur()
+	model.advancedFocus = -1
+	model.focusAdvanced()
+	model.subjectFocus = -1
+	model.focusSubject()
+
+	model.step = step
+	switch step {
+	case stepAudioPath:
+		if model.pathTyping {
+			model.audioPathInput.Focus()
+		}
+	case stepRecordSettings:
+		model.recordDeviceInput.Focus()
+	case stepLyrics:
+		model.lyricsInput.Focus()
+	case stepOverlayText:
+		model.overlayFocus = 0
+		model.overlayTitleInput.Focus()
+	case stepDuration:
+		model.durationInput.Focus()
+	case stepAdvanced:
+		model.advancedFocus = 0
+		model.focusAdvanced()
+	case stepSubject:
+		model.subjectFocus = 0
+		model.focusSubject()
+	}
+}
+
+func (model Model) viewConfirm() string {
+	lines := []string{headerStyle.Render("Confirm"), ""}
+	start := "Start render"
+	if model.confirmIdx == 0 {
+		lines = append(lines, "> "+highlight.Render(start), "")
+	} else {
+		lines = append(lines, "  "+start, "")
+	}
+	for index, row := range model.confirmRows() {
+		line := fmt.Sprintf("%-13s %s", row.label+":", row.value)
+		if index+1 == model.confirmIdx {
+			lines = append(lines, "> "+highlight.Render(line))
+		} else {
+			lines = append(lines, "  "+line)
+		}
+	}
+	lines = append(lines, "", subtle.Render("Use ↑/↓ and Enter to start or edit a field, Esc to go back"))
+	return strings.Join(lines, "\n")
+}
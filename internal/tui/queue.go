@@ -0,0 +1,334 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/audio2videoAI/internal/jobs"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	jobQueued  = "queued"
+	jobActive  = "running"
+	jobPicking = "pick variation"
+	jobDone    = "done"
+	jobFailed  = "failed"
+)
+
+type queuedJob struct {
+	id             int
+	input          jobs.JobInput
+	status         string
+	message        string
+	percent        float64
+	events         []jobs.Event
+	transcript     string
+	transcriptPath string
+	result         *jobs.Result
+	variations     []jobs.Variation
+	err            error
+	eventChan      <-chan jobs.Event
+}
+
+func (job queuedJob) label() string {
+	label := fmt.Sprintf("#%d %s", job.id, filepath.Base(job.input.AudioPath))
+	if job.input.StylePreset != "" {
+		label += " · " + job.input.StylePreset
+	}
+	return label
+}
+
+func (job queuedJob) finished() bool {
+	return job.status == jobPicking || job.status == jobDone || job.status == jobFailed
+}
+
+func (model *Model) job(id int) *queuedJob {
+	for index := range model.queue {
+		if model.queue[index].id == id {
+			return &model.queue[index]
+		}
+	}
+	return nil
+}
+
+func (model Model) enqueue(input jobs.JobInput) (Model, tea.Cmd) {
+	model.nextJobID++
+	model.queue = append(model.queue, queuedJob{id: model.nextJobID, input: input, status: jobQueued, message: "Waiting to start"})
+	model.queueIdx = len(model.queue) - 1
+	model.step = stepRunning
+	model.refreshLog()
+	return model, model.startJobCmd(model.nextJobID, input)
+}
+
+func (model Model) restartJob(id int, input jobs.JobInput) (Model, tea.Cmd) {
+	for index := range model.queue {
+		job := &model.queue[index]
+		if job.id != id {
+			continue
+		}
+		job.input = input
+		job.status = jobQueued
+		job.message = "Waiting to start"
+		job.percent = 0
+		job.err = nil
+		model.queueIdx = index
+	}
+	model.step = stepRunning
+	model.refreshLog()
+	return model, model.startJobCmd(id, input)
+}
+
+func (model Model) newWizard() (Model, tea.Cmd) {
+	fresh := NewModel(model.config, model.runner)
+	fresh.preparation = model.preparation
+	fresh.preparedFor = model.preparedFor
+	fresh.queue = model.queue
+	fresh.nextJobID = model.nextJobID
+	fresh.queueIdx = model.queueIdx
+	fresh.refreshLog()
+	return fresh, fetchSchemaCmd(fresh.runner, "")
+}
+
+func (model *Model) openJob(id int) {
+	job := model.job(id)
+	if job == nil || !job.finished() {
+		return
+	}
+	model.viewJob = id
+	model.lastInput = job.input
+	model.err = job.err
+	model.result = job.result
+	model.variations = job.variations
+	model.doneIdx = 0
+	switch job.status {
+	case jobPicking:
+		model.variationIdx = 0
+		model.status = "Variations ready"
+		model.step = stepVariations
+	case jobFailed:
+		model.status = "Job failed"
+		model.step = stepDone
+	default:
+		model.status = "Video ready"
+		model.step = stepDone
+	}
+}
+
+func (model Model) jobStarted(msg jobStartedMsg) (tea.Model, tea.Cmd) {
+	if job := model.job(msg.id); job != nil {
+		job.eventChan = msg.events
+	}
+	return model, tea.Batch(listenEventCmd(msg.id, msg.events), listenDoneCmd(msg.done))
+}
+
+func (model Model) jobProgress(msg jobProgressMsg) (tea.Model, tea.Cmd) {
+	job := model.job(msg.id)
+	if job == nil {
+		return model, nil
+	}
+	if msg.event.Time.IsZero() {
+		msg.event.Time = time.Now()
+	}
+	job.events = append(job.events, msg.event)
+	if msg.event.Stage == jobs.LogsStage {
+		model.refreshLog()
+		return model, listenEventCmd(msg.id, job.eventChan)
+	}
+	if msg.event.Transcript != "" {
+		job.transcript = msg.event.Transcript
+		job.transcriptPath = msg.event.TranscriptPath
+	}
+	job.status = jobActive
+	if msg.event.Stage == "queue" {
+		job.status = jobQueued
+	}
+	job.message = msg.event.Message
+	if msg.event.Progress > 0 {
+		job.percent = msg.event.Progress
+	}
+	model.refreshLog()
+	return model, listenEventCmd(msg.id, job.eventChan)
+}
+
+func (model Model) jobFinished(msg jobFinishedMsg) (tea.Model, tea.Cmd) {
+	if msg.input.Preparation != nil {
+		model.preparation = msg.input.Preparation
+		model.preparedFor = msg.input.AudioPath
+	}
+	job := model.job(msg.id)
+	if job == nil {
+		return model, nil
+	}
+	job.input = msg.input
+	job.err = msg.err
+	switch {
+	case msg.err != nil:
+		job.status = jobFailed
+		job.message = msg.err.Error()
+	case len(msg.variations) > 0:
+		job.status = jobPicking
+		job.message = "Variations ready"
+		job.variations = msg.variations
+	default:
+		job.status = jobDone
+		job.message = "Video ready"
+		job.percent = 1
+		job.result = &msg.result
+	}
+	if model.step == stepRunning && model.queueIdx < len(model.queue) && model.queue[model.queueIdx].id == msg.id {
+		model.openJob(msg.id)
+	}
+	model.refreshLog()
+	return model, nil
+}
+
+func (model Model) handleQueueKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if scrolled, ok := model.scrollLog(msg); ok {
+		return scrolled, nil
+	}
+	switch msg.String() {
+	case "up", "k":
+		if len(model.queue) > 0 {
+			model.queueIdx = (model.queueIdx + len(model.queue) - 1) % len(model.queue)
+		}
+	case "down", "j":
+		if len(model.queue) > 0 {
+			model.queueIdx = (model.queueIdx + 1) % len(model.queue)
+		}
+	case "enter":
+		if model.queueIdx < len(model.queue) {
+			model.openJob(model.queue[model.queueIdx].id)
+		}
+	case "n":
+		return model.newWizard()
+	case "c":
+		var remaining []queuedJob
+		for _, job := range model.queue {
+			if job.status != jobDone && job.status != jobFailed {
+				remaining = append(remaining, job)
+			}
+		}
+		model.queue = remaining
+		model.queueIdx = 0
+	}
+	model.refreshLog()
+	return model, nil
+}
+
+func (model Model) startJobCmd(id int, input jobs.JobInput) tea.Cmd {
+	runner := model.runner
+	return func() tea.Msg {
+		events := make(chan jobs.Event)
+		done := make(chan jobFinishedMsg, 1)
+		ctx := context.Background()
+		go func() {
+			events <- jobs.Event{Stage: "queue", Message: "Waiting for a free slot"}
+			var finished jobFinishedMsg
+			release, err := runner.Queue.Acquire(ctx)
+			if err != nil {
+				finished = jobFinishedMsg{input: input, err: err}
+			} else {
+				finished = runJob(ctx, runner, input, events)
+				release()
+			}
+			close(events)
+			finished.id = id
+			done <- finished
+		}()
+		return jobStartedMsg{id: id, events: events, done: done}
+	}
+}
+
+func runJob(ctx context.Context, runner *jobs.Runner, input jobs.JobInput, events chan<- jobs.Event) jobFinishedMsg {
+	if input.Preparation == nil {
+		preparation, err := runner.Prepare(ctx, input, events)
+		if err != nil {
+			jobs.RecordFailure(input, err)
+			return jobFinishedMsg{input: input, err: err}
+		}
+		input.Preparation = &preparation
+	}
+	if input.Variations > 1 && input.Variation == nil {
+		variations, err := runner.RenderVariations(ctx, input, events)
+		return jobFinishedMsg{input: input, variations: variations, err: err}
+	}
+	result, err := runner.Run(ctx, input, events)
+	return jobFinishedMsg{result: result, input: input, err: err}
+}
+
+func (model Model) queueCounts() (int, int) {
+	running, queued := 0, 0
+	for _, job := range model.queue {
+		switch job.status {
+		case jobActive:
+			running++
+		case jobQueued:
+			queued++
+		}
+	}
+	return running, queued
+}
+
+func (model Model) viewJobRow(job queuedJob) string {
+	return fmt.Sprintf("%-32s %s  %s", truncateText(job.label(), 32), model.progress.ViewAs(job.percent), truncateText(job.status+": "+job.message, 60))
+}
+
+func (model Model) viewRunning() string {
+	running, queued := model.queueCounts()
+	summary := fmt.Sprintf("%d running, %d queued", running, queued)
+	if limit := model.runner.Queue.Limit(); limit > 0 {
+		summary += fmt.Sprintf(", up to %d at a time", limit)
+	}
+	lines := []string{headerStyle.Render("Jobs"), subtle.Render(summary), ""}
+	if len(model.queue) == 0 {
+		lines = append(lines, subtle.Render("No jobs"))
+	}
+	for index, job := range model.queue {
+		row := model.viewJobRow(job)
+		switch {
+		case index == model.queueIdx:
+			lines = append(lines, "> "+highlight.Render(row))
+		case job.status == jobFailed:
+			lines = append(lines, "  "+warningStyle.Render(row))
+		default:
+			lines = append(lines, "  "+row)
+		}
+	}
+
+	if model.queueIdx < len(model.queue) {
+		job := model.queue[model.queueIdx]
+		if stages := formatStages(stageDurations(job.events)); stages != "" {
+			lines = append(lines, "", subtle.Render("Stages: ")+stages)
+		}
+		if job.transcript != "" {
+			lines = append(lines, "", subtle.Render("Transcript preview:"), truncateText(job.transcript, 280))
+			if job.transcriptPath != "" {
+				lines = append(lines, subtle.Render("Saved: "+job.transcriptPath))
+			}
+		}
+		if len(job.events) > 0 {
+			lines = append(lines, "", subtle.Render(fmt.Sprintf("Event log (%.0f%%):", model.logView.ScrollPercent()*100)), model.logView.View())
+		}
+		if job.err != nil {
+			lines = append(lines, "", subtle.Render("Error:"))
+			lines = append(lines, errorDetail(job.err)...)
+		}
+	}
+	lines = append(lines, "", subtle.Render("↑/↓ to select, PgUp/PgDn/Home/End to scroll the log, Enter to open a finished job, n for a new job, c to clear finished jobs"))
+	return strings.Join(lines, "\n")
+}
+
+func (model Model) viewQueuePanel() string {
+	if len(model.queue) == 0 || model.step == stepRunning {
+		return ""
+	}
+	lines := []string{subtle.Render("Jobs:")}
+	for _, job := range model.queue {
+		lines = append(lines, "  "+model.viewJobRow(job))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,175 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/audio2videoAI/internal/jobs"
+	"github.com/audio2videoAI/internal/video"
+	"github.com/audio2videoAI/pkg/config"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type settingField struct {
+	key   string
+	label string
+}
+
+func settingFields() []settingField {
+	return []settingField{
+		{key: "REPLICATE_MODEL", label: "Replicate model"},
+		{key: "REPLICATE_V2V_MODEL", label: "Video-to-video model"},
+		{key: "OUTPUT_DIR", label: "Output directory"},
+		{key: "TRANSCRIBE_ENABLED", label: "Transcribe (true/false)"},
+		{key: "WHISPER_MODEL", label: "Whisper model"},
+		{key: "JOB_POLL_INTERVAL", label: "Poll interval"},
+		{key: "JOB_CONCURRENCY", label: "Concurrent jobs"},
+		{key: "HTTP_TIMEOUT", label: "HTTP timeout"},
+		{key: "THUMBNAILS_ENABLED", label: "Thumbnails (true/false)"},
+		{key: "PREVIEW_ENABLED", label: "Preview (true/false)"},
+		{key: "STORYBOARD_ENABLED", label: "Storyboard (true/false)"},
+	}
+}
+
+func (model *Model) openSettings() {
+	fields := settingFields()
+	model.settingsInputs = make([]textinput.Model, len(fields))
+	for index, field := range fields {
+		input := textinput.New()
+		input.SetValue(model.config.Setting(field.key).Value)
+		model.settingsInputs[index] = input
+	}
+	model.settingsFocus = 0
+	model.settingsProblems = nil
+	model.settingsStatus = ""
+	model.focusSettings()
+	model.step = stepSettings
+}
+
+func (model *Model) focusSettings() {
+	for index := range model.settingsInputs {
+		if index == model.settingsFocus {
+			model.settingsInputs[index].Focus()
+		} else {
+			model.settingsInputs[index].Blur()
+		}
+	}
+}
+
+func (model Model) handleSettingsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	fields := len(model.settingsInputs)
+	switch msg.String() {
+	case "tab", "down":
+		model.settingsFocus = (model.settingsFocus + 1) % fields
+		model.focusSettings()
+		return model, nil
+	case "shift+tab", "up":
+		model.settingsFocus = (model.settingsFocus + fields - 1) % fields
+		model.focusSettings()
+		return model, nil
+	case "esc":
+		model.step = stepInputType
+		return model, nil
+	case "enter":
+		return model.applySettings(false)
+	case "ctrl+s":
+		return model.applySettings(true)
+	}
+	var cmd tea.Cmd
+	model.settingsInputs[model.settingsFocus], cmd = model.settingsInputs[model.settingsFocus].Update(msg)
+	return model, cmd
+}
+
+func (model Model) applySettings(persist bool) (tea.Model, tea.Cmd) {
+	changes := map[string]string{}
+	for index, field := range settingFields() {
+		value := strings.TrimSpace(model.settingsInputs[index].Value())
+		if value != model.config.Setting(field.key).Value {
+			changes[field.key] = value
+		}
+	}
+
+	cfg, problems, err := model.config.With(changes)
+	if err != nil {
+		model.settingsProblems = nil
+		model.settingsStatus = err.Error()
+		return model, nil
+	}
+	model.settingsProblems = problems
+	if config.HasErrors(problems) {
+		model.settingsStatus = "Fix the errors above to apply"
+		return model, nil
+	}
+
+	runner, err := jobs.NewRunner(cfg)
+	if model.runner != nil && model.runner.Queue != nil {
+		runner.Queue = model.runner.Queue
+		runner.Queue.Resize(cfg.JobConcurrency)
+	}
+	model.config = cfg
+	model.runner = runner
+	model.provider = ""
+	model.schema = nil
+	model.schemaErr = nil
+	model.aspects = aspectOptions()
+	model.aspectIdx = 0
+	model.presetIdx = 0
+	model.styleIdx = 0
+	model.overlayIdx = 0
+	model.preparation = nil
+	model.overlayPresets, model.overlayErr = video.LoadOverlayPresets(cfg.OverlayPresetsPath)
+	model.settingsStatus = fmt.Sprintf("Applied %d change(s)", len(changes))
+	if err != nil {
+		model.settingsStatus += "; presets: " + err.Error()
+	}
+
+	if persist && len(changes) > 0 {
+		path, err := cfg.Save(changes)
+		if err != nil {
+			model.settingsStatus = "Applied, but saving failed: " + err.Error()
+			return model, fetchSchemaCmd(runner, "")
+		}
+		model.settingsStatus = fmt.Sprintf("Applied and saved %d change(s) to %s", len(changes), path)
+		var shadowed []string
+		for key := range changes {
+			if os.Getenv(key) != "" {
+				shadowed = append(shadowed, key)
+			}
+		}
+		if len(shadowed) > 0 {
+			model.settingsStatus += fmt.Sprintf(" (environment overrides %s on the next start)", strings.Join(shadowed, ", "))
+		}
+	}
+	return model, fetchSchemaCmd(runner, "")
+}
+
+func (model Model) viewSettings() string {
+	lines := []string{headerStyle.Render("Settings"), ""}
+	for index, field := range settingFields() {
+		label := field.label
+		if index == model.settingsFocus {
+			label = highlight.Render(label)
+		}
+		source := model.config.Setting(field.key).Source
+		lines = append(lines, fmt.Sprintf("%s %s", label, subtle.Render("("+field.key+", "+source+")")), model.settingsInputs[index].View())
+	}
+	for _, problem := range model.settingsProblems {
+		style := subtle
+		if problem.Severity == config.SeverityError {
+			style = warningStyle
+		}
+		lines = append(lines, style.Render(fmt.Sprintf("%s: %s", problem.Severity, problem.Error())))
+	}
+	if model.settingsStatus != "" {
+		lines = append(lines, "", statusStyle.Render(model.settingsStatus))
+	}
+	profile := model.config.Profile
+	if profile == "" {
+		profile = "none"
+	}
+	lines = append(lines, "", subtle.Render(fmt.Sprintf("Profile: %s · Config file: %s", profile, model.config.SavePath())))
+	lines = append(lines, subtle.Render("Tab to switch fields, Enter to apply, Ctrl+S to apply and save, Esc to go back"))
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,186 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/audio2videoAI/internal/audio"
+)
+
+const (
+	EffectZoom       = "zoom"
+	EffectFlash      = "flash"
+	EffectBrightness = "brightness"
+)
+
+const (
+	zoomAmount        = 0.06
+	zoomDecaySeconds  = 0.15
+	flashSeconds      = 0.08
+	flashBrightness   = 0.25
+	brightnessRange   = 0.08
+	brightnessSegment = 0.5
+)
+
+func ApplyEffects(ctx context.Context, ffmpegPath string, effects []string, analysis audio.Analysis, inputPath, outputDir string) (string, error) {
+	if len(effects) == 0 {
+		return inputPath, nil
+	}
+	if ffmpegPath == "" {
+		ffmpegPath = "ffmpeg"
+	}
+	info, err := Probe(ctx, ffmpegPath, inputPath)
+	if err != nil {
+		return "", err
+	}
+	filter, err := EffectsFilter(effects, analysis, info)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return "", err
+	}
+	outputPath := filepath.Join(outputDir, fmt.Sprintf("fx-%d.mp4", time.Now().UnixNano()))
+
+	cmd := exec.CommandContext(
+		ctx,
+		ffmpegPath,
+		"-y",
+		"-i", inputPath,
+		"-filter_complex", filter,
+		"-map", "[out]",
+		"-map", "0:a?",
+		"-c:v", "libx264",
+		"-pix_fmt", "yuv420p",
+		"-c:a", "copy",
+		outputPath,
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("ffmpeg effects failed: %s", strings.TrimSpace(string(output)))
+	}
+	return outputPath, nil
+}
+
+func EffectsFilter(effects []string, analysis audio.Analysis, info Info) (string, error) {
+	duration := info.Duration
+	if duration <= 0 {
+		duration = analysis.Duration
+	}
+
+	var filters []string
+	var brightnessTerms []string
+	for _, effect := range effects {
+		switch strings.ToLower(strings.TrimSpace(effect)) {
+		case EffectZoom:
+			if info.Width <= 0 || info.Height <= 0 {
+				return "", fmt.Errorf("video size unknown, cannot apply zoom pulses")
+			}
+			if pulse := beatPulse(analysis.Beats, "it"); pulse != "" {
+				fps := info.FPS
+				if fps <= 0 {
+					fps = 25
+				}
+				filters = append(filters, fmt.Sprintf(
+					"zoompan=z='1+%.3f*%s':x='iw/2-(iw/zoom/2)':y='ih/2-(ih/zoom/2)':d=1:s=%dx%d:fps=%.3f",
+					zoomAmount, pulse, info.Width, info.Height, fps,
+				))
+			}
+		case EffectFlash:
+			if flash := flashTerms(analysis.Onsets, duration); flash != "" {
+				brightnessTerms = append(brightnessTerms, flash)
+			}
+		case EffectBrightness:
+			if level := envelopeTerms(analysis.Envelope, duration); level != "" {
+				brightnessTerms = append(brightnessTerms, level)
+			}
+		default:
+			return "", fmt.Errorf("unknown effect: %s", effect)
+		}
+	}
+	if len(brightnessTerms) > 0 {
+		filters = append(filters, fmt.Sprintf("eq=brightness='%s':eval=frame", strings.Join(brightnessTerms, "+")))
+	}
+	if len(filters) == 0 {
+		filters = append(filters, "null")
+	}
+	return "[0:v]" + strings.Join(filters, ",") + ",setsar=1[out]", nil
+}
+
+func beatPulse(beats []float64, timeVar string) string {
+	if len(beats) < 2 {
+		return ""
+	}
+	start := beats[0]
+	period := beats[1] - beats[0]
+	if period <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(
+		"if(gte(%s,%.3f),max(0,1-mod(%s-%.3f,%.3f)/%.3f),0)",
+		timeVar, start, timeVar, start, period, zoomDecaySeconds,
+	)
+}
+
+func flashTerms(onsets []float64, duration float64) string {
+	var terms []string
+	for _, onset := range onsets {
+		if duration > 0 && onset >= duration {
+			break
+		}
+		terms = append(terms, fmt.Sprintf("between(t,%.2f,%.2f)*%.2f", onset, onset+flashSeconds, flashBrightness))
+	}
+	return strings.Join(terms, "+")
+}
+
+func envelopeTerms(envelope []audio.EnvelopePoint, duration float64) string {
+	if len(envelope) == 0 {
+		return ""
+	}
+	var segments []float64
+	var sums []float64
+	var counts []int
+	for _, point := range envelope {
+		if duration > 0 && point.Time >= duration {
+			break
+		}
+		index := int(point.Time / brightnessSegment)
+		for len(sums) <= index {
+			sums = append(sums, 0)
+			counts = append(counts, 0)
+		}
+		sums[index] += point.RMS
+		counts[index]++
+	}
+	low, high := math.Inf(1), math.Inf(-1)
+	for index := range sums {
+		level := -90.0
+		if counts[index] > 0 {
+			level = sums[index] / float64(counts[index])
+		}
+		segments = append(segments, level)
+		low = math.Min(low, level)
+		high = math.Max(high, level)
+	}
+	if len(segments) == 0 || high-low < 1 {
+		return ""
+	}
+
+	var terms []string
+	for index, level := range segments {
+		normalized := (level-low)/(high-low)*2 - 1
+		value := normalized * brightnessRange
+		if math.Abs(value) < 0.005 {
+			continue
+		}
+		start := float64(index) * brightnessSegment
+		terms = append(terms, fmt.Sprintf("gte(t,%.2f)*lt(t,%.2f)*(%.3f)", start, start+brightnessSegment, value))
+	}
+	return strings.Join(terms, "+")
+}
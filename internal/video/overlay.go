@@ -0,0 +1,160 @@
+package video
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type Overlay struct {
+	LogoPath string  `json:"logo_path"`
+	Position string  `json:"position"`
+	Opacity  float64 `json:"opacity"`
+	Title    string  `json:"title"`
+	Artist   string  `json:"artist"`
+	FontPath string  `json:"font_path"`
+	Start    float64 `json:"start"`
+	End      float64 `json:"end"`
+}
+
+type OverlayPreset struct {
+	Name string `json:"name"`
+	Overlay
+}
+
+func (overlay Overlay) Empty() bool {
+	return strings.TrimSpace(overlay.LogoPath) == "" && strings.TrimSpace(overlay.Title) == "" && strings.TrimSpace(overlay.Artist) == ""
+}
+
+func LoadOverlayPresets(path string) ([]OverlayPreset, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, nil
+	}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var presets []OverlayPreset
+	if err := json.Unmarshal(content, &presets); err != nil {
+		return nil, fmt.Errorf("overlay presets %s: %w", path, err)
+	}
+	return presets, nil
+}
+
+func ApplyOverlay(ctx context.Context, ffmpegPath string, overlay Overlay, inputPath, outputDir string) (string, error) {
+	if overlay.Empty() {
+		return inputPath, nil
+	}
+	if ffmpegPath == "" {
+		ffmpegPath = "ffmpeg"
+	}
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return "", err
+	}
+	outputPath := filepath.Join(outputDir, fmt.Sprintf("branded-%d.mp4", time.Now().UnixNano()))
+
+	args := []string{"-y", "-i", inputPath}
+	if overlay.LogoPath != "" {
+		args = append(args, "-i", overlay.LogoPath)
+	}
+	args = append(args,
+		"-filter_complex", overlayFilter(overlay),
+		"-map", "[out]",
+		"-map", "0:a?",
+		"-c:v", "libx264",
+		"-pix_fmt", "yuv420p",
+		"-c:a", "copy",
+		outputPath,
+	)
+
+	cmd := exec.CommandContext(ctx, ffmpegPath, args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("ffmpeg overlay failed: %s", strings.TrimSpace(string(output)))
+	}
+	return outputPath, nil
+}
+
+func overlayFilter(overlay Overlay) string {
+	enable := ""
+	if overlay.End > overlay.Start {
+		enable = fmt.Sprintf(":enable='between(t,%.2f,%.2f)'", overlay.Start, overlay.End)
+	}
+
+	var chains []string
+	current := "[0:v]"
+	if overlay.LogoPath != "" {
+		opacity := overlay.Opacity
+		if opacity <= 0 || opacity > 1 {
+			opacity = 1
+		}
+		x, y := overlayPosition(overlay.Position)
+		chains = append(chains,
+			"[1:v][0:v]scale2ref=w=main_w/6:h=ow/a[logo][base]",
+			fmt.Sprintf("[logo]format=rgba,colorchannelmixer=aa=%.2f[logo]", opacity),
+			fmt.Sprintf("[base][logo]overlay=x=%s:y=%s%s[branded]", x, y, enable),
+		)
+		current = "[branded]"
+	}
+
+	var texts []string
+	if title := strings.TrimSpace(overlay.Title); title != "" {
+		texts = append(texts, drawText(overlay, title, "h/20", "h*0.78", enable))
+	}
+	if artist := strings.TrimSpace(overlay.Artist); artist != "" {
+		texts = append(texts, drawText(overlay, artist, "h/30", "h*0.78+h/16", enable))
+	}
+	if len(texts) == 0 {
+		texts = append(texts, "null")
+	}
+	chains = append(chains, current+strings.Join(texts, ",")+"[out]")
+	return strings.Join(chains, ";")
+}
+
+func drawText(overlay Overlay, text, fontSize, y, enable string) string {
+	options := []string{
+		"expansion=none",
+		"text=" + escapeFilterValue(text),
+		"fontsize=" + fontSize,
+		"fontcolor=white",
+		"shadowcolor=black@0.6",
+		"shadowx=2",
+		"shadowy=2",
+		"x=(w-text_w)/2",
+		"y=" + y,
+	}
+	if overlay.FontPath != "" {
+		options = append(options, "fontfile="+escapeFilterValue(overlay.FontPath))
+	}
+	return "drawtext=" + strings.Join(options, ":") + enable
+}
+
+func overlayPosition(position string) (string, string) {
+	const margin = "24"
+	switch strings.ToLower(strings.TrimSpace(position)) {
+	case "top-left":
+		return margin, margin
+	case "bottom-left":
+		return margin, "main_h-overlay_h-" + margin
+	case "bottom-right":
+		return "main_w-overlay_w-" + margin, "main_h-overlay_h-" + margin
+	case "center":
+		return "(main_w-overlay_w)/2", "(main_h-overlay_h)/2"
+	default:
+		return "main_w-overlay_w-" + margin, margin
+	}
+}
+
+func escapeFilterValue(value string) string {
+	optionLevel := strings.NewReplacer(`\`, `\\`, `'`, `\'`, `:`, `\:`).Replace(value)
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`, `[`, `\[`, `]`, `\]`, `,`, `\,`, `;`, `\;`).Replace(optionLevel)
+}
@@ -0,0 +1,80 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type PreviewConfig struct {
+	Enabled bool
+	Width   int
+	FPS     int
+}
+
+type Preview struct {
+	GIFPath  string
+	WebPPath string
+}
+
+func GeneratePreview(ctx context.Context, ffmpegPath string, config PreviewConfig, videoPath, outputDir string) (Preview, error) {
+	if !config.Enabled {
+		return Preview{}, nil
+	}
+	if ffmpegPath == "" {
+		ffmpegPath = "ffmpeg"
+	}
+	if config.Width <= 0 {
+		config.Width = 480
+	}
+	if config.FPS <= 0 {
+		config.FPS = 12
+	}
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return Preview{}, err
+	}
+
+	base := filepath.Join(outputDir, fmt.Sprintf("preview-%d", time.Now().UnixNano()))
+	scale := fmt.Sprintf("fps=%d,scale=%d:-2:flags=lanczos", config.FPS, config.Width)
+	preview := Preview{GIFPath: base + ".gif", WebPPath: base + ".webp"}
+
+	gifCmd := exec.CommandContext(
+		ctx,
+		ffmpegPath,
+		"-y",
+		"-i", videoPath,
+		"-filter_complex", scale+",split[a][b];[a]palettegen=stats_mode=diff[p];[b][p]paletteuse=dither=bayer:bayer_scale=5",
+		"-loop", "0",
+		"-an",
+		preview.GIFPath,
+	)
+	var failures []error
+	if output, err := gifCmd.CombinedOutput(); err != nil {
+		preview.GIFPath = ""
+		failures = append(failures, fmt.Errorf("ffmpeg gif preview failed: %s", strings.TrimSpace(string(output))))
+	}
+
+	webpCmd := exec.CommandContext(
+		ctx,
+		ffmpegPath,
+		"-y",
+		"-i", videoPath,
+		"-vf", scale,
+		"-c:v", "libwebp",
+		"-lossless", "0",
+		"-q:v", "70",
+		"-loop", "0",
+		"-an",
+		preview.WebPPath,
+	)
+	if output, err := webpCmd.CombinedOutput(); err != nil {
+		preview.WebPPath = ""
+		failures = append(failures, fmt.Errorf("ffmpeg webp preview failed: %s", strings.TrimSpace(string(output))))
+	}
+	return preview, errors.Join(failures...)
+}
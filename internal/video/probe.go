@@ -0,0 +1,55 @@
+package video
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"regexp"
+	"strconv"
+)
+
+type Info struct {
+	Width    int
+	Height   int
+	FPS      float64
+	Duration float64
+}
+
+var (
+	streamSizePattern = regexp.MustCompile(`Video: .*?, (\d{2,5})x(\d{2,5})`)
+	streamFPSPattern  = regexp.MustCompile(`([\d.]+) fps`)
+	durationPattern   = regexp.MustCompile(`Duration: (\d{2}):(\d{2}):(\d{2}\.\d+)`)
+)
+
+func Probe(ctx context.Context, ffmpegPath, inputPath string) (Info, error) {
+	if ffmpegPath == "" {
+		ffmpegPath = "ffmpeg"
+	}
+	cmd := exec.CommandContext(ctx, ffmpegPath, "-hide_banner", "-i", inputPath)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	// ffmpeg exits non-zero without an output file; the stream info is still printed.
+	_ = cmd.Run()
+	if err := ctx.Err(); err != nil {
+		return Info{}, err
+	}
+	return parseInfo(stderr.String()), nil
+}
+
+func parseInfo(output string) Info {
+	var info Info
+	if matches := streamSizePattern.FindStringSubmatch(output); len(matches) == 3 {
+		info.Width, _ = strconv.Atoi(matches[1])
+		info.Height, _ = strconv.Atoi(matches[2])
+	}
+	if matches := streamFPSPattern.FindStringSubmatch(output); len(matches) == 2 {
+		info.FPS, _ = strconv.ParseFloat(matches[1], 64)
+	}
+	if matches := durationPattern.FindStringSubmatch(output); len(matches) == 4 {
+		hours, _ := strconv.ParseFloat(matches[1], 64)
+		minutes, _ := strconv.ParseFloat(matches[2], 64)
+		seconds, _ := strconv.ParseFloat(matches[3], 64)
+		info.Duration = hours*3600 + minutes*60 + seconds
+	}
+	return info
+}
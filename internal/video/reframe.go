@@ -0,0 +1,110 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	ReframeCrop = "crop"
+	ReframePad  = "pad"
+	ReframeBlur = "blur"
+)
+
+type Variant struct {
+	AspectRatio string
+	Path        string
+}
+
+func SocialAspects() []string {
+	return []string{"16:9", "4:5", "1:1", "9:16"}
+}
+
+func Reframe(ctx context.Context, ffmpegPath, inputPath, outputDir, aspectRatio, strategy string) (string, error) {
+	if ffmpegPath == "" {
+		ffmpegPath = "ffmpeg"
+	}
+	width, height, err := frameSize(aspectRatio)
+	if err != nil {
+		return "", err
+	}
+	filter, err := reframeFilter(strategy, width, height)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return "", err
+	}
+	outputPath := filepath.Join(outputDir, fmt.Sprintf("reframe-%s-%d.mp4", strings.ReplaceAll(aspectRatio, ":", "x"), time.Now().UnixNano()))
+
+	cmd := exec.CommandContext(
+		ctx,
+		ffmpegPath,
+		"-y",
+		"-i", inputPath,
+		"-filter_complex", filter,
+		"-map", "[out]",
+		"-map", "0:a?",
+		"-c:v", "libx264",
+		"-pix_fmt", "yuv420p",
+		"-c:a", "copy",
+		outputPath,
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("ffmpeg reframe failed: %s", strings.TrimSpace(string(output)))
+	}
+	return outputPath, nil
+}
+
+func ReframeAll(ctx context.Context, ffmpegPath, inputPath, outputDir, strategy string, aspects []string) ([]Variant, error) {
+	var variants []Variant
+	var failures []error
+	for _, aspect := range aspects {
+		path, err := Reframe(ctx, ffmpegPath, inputPath, outputDir, aspect, strategy)
+		if err != nil {
+			failures = append(failures, fmt.Errorf("%s: %w", aspect, err))
+			continue
+		}
+		variants = append(variants, Variant{AspectRatio: aspect, Path: path})
+	}
+	return variants, errors.Join(failures...)
+}
+
+func reframeFilter(strategy string, width, height int) (string, error) {
+	size := fmt.Sprintf("%d:%d", width, height)
+	switch strings.ToLower(strings.TrimSpace(strategy)) {
+	case ReframeCrop:
+		return fmt.Sprintf("[0:v]scale=%s:force_original_aspect_ratio=increase,crop=%s,setsar=1[out]", size, size), nil
+	case ReframePad:
+		return fmt.Sprintf("[0:v]scale=%s:force_original_aspect_ratio=decrease,pad=%s:(ow-iw)/2:(oh-ih)/2,setsar=1[out]", size, size), nil
+	case ReframeBlur, "":
+		return fmt.Sprintf(
+			"[0:v]split[bg][fg];[bg]scale=%s:force_original_aspect_ratio=increase,crop=%s,boxblur=20:2[bg];[fg]scale=%s:force_original_aspect_ratio=decrease[fg];[bg][fg]overlay=(W-w)/2:(H-h)/2,setsar=1[out]",
+			size, size, size,
+		), nil
+	default:
+		return "", fmt.Errorf("unknown reframe strategy: %s", strategy)
+	}
+}
+
+func frameSize(aspectRatio string) (int, int, error) {
+	switch strings.TrimSpace(aspectRatio) {
+	case "16:9":
+		return 1920, 1080, nil
+	case "9:16":
+		return 1080, 1920, nil
+	case "1:1":
+		return 1080, 1080, nil
+	case "4:5":
+		return 1080, 1350, nil
+	default:
+		return 0, 0, fmt.Errorf("unsupported aspect ratio: %s", aspectRatio)
+	}
+}
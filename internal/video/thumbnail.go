@@ -0,0 +1,103 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+type ThumbnailConfig struct {
+	Enabled bool
+	Format  string
+	Count   int
+}
+
+func ExtractThumbnails(ctx context.Context, ffmpegPath string, config ThumbnailConfig, videoPath, outputDir string, hookTime float64) ([]string, error) {
+	if !config.Enabled {
+		return nil, nil
+	}
+	if ffmpegPath == "" {
+		ffmpegPath = "ffmpeg"
+	}
+	format := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(config.Format), "."))
+	switch format {
+	case "", "jpeg":
+		format = "jpg"
+	case "jpg", "png":
+	default:
+		return nil, fmt.Errorf("unsupported thumbnail format: %s", config.Format)
+	}
+	if config.Count <= 0 {
+		config.Count = 4
+	}
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return nil, err
+	}
+
+	prefix := filepath.Join(outputDir, fmt.Sprintf("thumb-%d", time.Now().UnixNano()))
+	scenePattern := fmt.Sprintf("%s-scene-%%02d.%s", prefix, format)
+	if err := runThumbnail(ctx, ffmpegPath, format,
+		"-i", videoPath,
+		"-vf", "select='gt(scene,0.3)'",
+		"-vsync", "vfr",
+		"-frames:v", fmt.Sprintf("%d", config.Count),
+		scenePattern,
+	); err != nil {
+		return nil, err
+	}
+	thumbnails, err := filepath.Glob(fmt.Sprintf("%s-scene-*.%s", prefix, format))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(thumbnails)
+
+	if len(thumbnails) == 0 {
+		posterPath := fmt.Sprintf("%s-poster.%s", prefix, format)
+		if err := runThumbnail(ctx, ffmpegPath, format, "-i", videoPath, "-vf", "thumbnail", "-frames:v", "1", posterPath); err != nil {
+			return nil, err
+		}
+		thumbnails = appendExisting(thumbnails, posterPath)
+	}
+
+	if hookTime < 0 {
+		return thumbnails, nil
+	}
+	hookPath := fmt.Sprintf("%s-hook.%s", prefix, format)
+	if err := runThumbnail(ctx, ffmpegPath, format,
+		"-ss", fmt.Sprintf("%.2f", hookTime),
+		"-i", videoPath,
+		"-frames:v", "1",
+		hookPath,
+	); err != nil {
+		return nil, err
+	}
+	return appendExisting(thumbnails, hookPath), nil
+}
+
+func runThumbnail(ctx context.Context, ffmpegPath, format string, args ...string) error {
+	output := args[len(args)-1]
+	args = append([]string{"-y"}, args[:len(args)-1]...)
+	if format == "jpg" {
+		args = append(args, "-q:v", "2")
+	}
+	args = append(args, output)
+
+	cmd := exec.CommandContext(ctx, ffmpegPath, args...)
+	combined, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("ffmpeg thumbnail failed: %s", strings.TrimSpace(string(combined)))
+	}
+	return nil
+}
+
+func appendExisting(paths []string, path string) []string {
+	if _, err := os.Stat(path); err != nil {
+		return paths
+	}
+	return append(paths, path)
+}
@@ -0,0 +1,42 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+func Trim(ctx context.Context, ffmpegPath, inputPath, outputDir string, start, duration float64) (string, error) {
+	if ffmpegPath == "" {
+		ffmpegPath = "ffmpeg"
+	}
+	if duration <= 0 {
+		return "", fmt.Errorf("trim duration must be positive")
+	}
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return "", err
+	}
+	outputPath := filepath.Join(outputDir, fmt.Sprintf("source-%d.mp4", time.Now().UnixNano()))
+
+	cmd := exec.CommandContext(
+		ctx,
+		ffmpegPath,
+		"-y",
+		"-ss", fmt.Sprintf("%.3f", start),
+		"-i", inputPath,
+		"-t", fmt.Sprintf("%.3f", duration),
+		"-an",
+		"-c:v", "libx264",
+		"-pix_fmt", "yuv420p",
+		outputPath,
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("ffmpeg trim failed: %s", strings.TrimSpace(string(output)))
+	}
+	return outputPath, nil
+}
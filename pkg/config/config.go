@@ -1,8 +1,10 @@
 package config
 
 import (
-	"os"
+	"fmt"
+	"math"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -13,7 +15,11 @@ type Config struct {
 	ReplicateAPIToken     string
 	ReplicateBaseURL      string
 	ReplicateModel        string
+	ReplicateV2VModel     string
 	ReplicatePreferWait   bool
+	ReplicatePromptChars  int
+	ReplicateCostPerSec   float64
+	ReplicateModelCosts   map[string]float64
 	TranscribeEnabled     bool
 	WhisperDockerPath     string
 	WhisperDockerImage    string
@@ -22,77 +28,164 @@ type Config struct {
 	WhisperAutoDownload   bool
 	OutputDir             string
 	FFmpegPath            string
+	ThumbnailsEnabled     bool
+	ThumbnailFormat       string
+	ThumbnailCount        int
+	PreviewEnabled        bool
+	PreviewWidth          int
+	PreviewFPS            int
+	OverlayPresetsPath    string
+	PresetsPath           string
+	PromptTemplatesDir    string
+	StoryboardEnabled     bool
+	StoryboardShotSeconds int
+	LLMAPIKey             string
+	LLMBaseURL            string
+	LLMModel              string
+	LLMPromptChars        int
 	RecordFormat          string
 	RecordDevice          string
 	RecordDurationSeconds int
 	JobPollInterval       time.Duration
+	JobConcurrency        int
 	HTTPTimeout           time.Duration
+
+	Profile  string
+	Files    []string
+	Settings []Setting
+
+	options Options
 }
 
-func Load() Config {
-	return Config{
-		ElevenLabsAPIKey:      getEnv("ELEVENLABS_API_KEY", ""),
-		ElevenLabsBaseURL:     getEnv("ELEVENLABS_BASE_URL", "https://api.elevenlabs.io"),
-		ElevenLabsEnhancePath: getEnv("ELEVENLABS_ENHANCE_PATH", "/v1/audio-isolation"),
-		OutputDir:             getEnv("OUTPUT_DIR", "./outputs"),
-		FFmpegPath:            getEnv("FFMPEG_PATH", "ffmpeg"),
-		ReplicateAPIToken:     getEnv("REPLICATE_API_TOKEN", ""),
-		ReplicateBaseURL:      getEnv("REPLICATE_BASE_URL", "https://api.replicate.com/v1"),
-		ReplicateModel:        getEnv("REPLICATE_MODEL", "minimax/video-01"),
-		ReplicatePreferWait:   getEnvBool("REPLICATE_PREFER_WAIT", true),
-		TranscribeEnabled:     getEnvBool("TRANSCRIBE_ENABLED", true),
-		WhisperDockerPath:     getEnv("WHISPER_DOCKER_PATH", "docker"),
-		WhisperDockerImage:    getEnv("WHISPER_DOCKER_IMAGE", "ghcr.io/ggml-org/whisper.cpp:main"),
-		WhisperModel:          getEnv("WHISPER_MODEL", "small"),
-		WhisperModelDir:       getEnv("WHISPER_MODEL_DIR", "./models"),
-		WhisperAutoDownload:   getEnvBool("WHISPER_AUTO_DOWNLOAD", true),
-		RecordFormat:          getEnv("AUDIO_RECORD_FORMAT", "alsa"),
-		RecordDevice:          getEnv("AUDIO_RECORD_DEVICE", "default"),
-		RecordDurationSeconds: getEnvInt("AUDIO_RECORD_SECONDS", 15),
-		JobPollInterval:       getEnvDuration("JOB_POLL_INTERVAL", 4*time.Second),
-		HTTPTimeout:           getEnvDuration("HTTP_TIMEOUT", 5*time.Minute),
+func Load(options Options) (Config, []Problem, error) {
+	values, err := newLoader(options)
+	if err != nil {
+		return Config{}, nil, err
+	}
+	cfg := Config{
+		ElevenLabsAPIKey:      values.getString("ELEVENLABS_API_KEY", ""),
+		ElevenLabsBaseURL:     values.getString("ELEVENLABS_BASE_URL", "https://api.elevenlabs.io"),
+		ElevenLabsEnhancePath: values.getString("ELEVENLABS_ENHANCE_PATH", "/v1/audio-isolation"),
+		OutputDir:             values.getString("OUTPUT_DIR", "./outputs"),
+		FFmpegPath:            values.getString("FFMPEG_PATH", "ffmpeg"),
+		ThumbnailsEnabled:     values.getBool("THUMBNAILS_ENABLED", true),
+		ThumbnailFormat:       values.getString("THUMBNAIL_FORMAT", "jpg"),
+		ThumbnailCount:        values.getInt("THUMBNAIL_COUNT", 4),
+		PreviewEnabled:        values.getBool("PREVIEW_ENABLED", false),
+		PreviewWidth:          values.getInt("PREVIEW_WIDTH", 480),
+		PreviewFPS:            values.getInt("PREVIEW_FPS", 12),
+		OverlayPresetsPath:    values.getString("OVERLAY_PRESETS_FILE", "./overlays.json"),
+		PresetsPath:           values.getString("PRESETS_FILE", "./presets.yaml"),
+		PromptTemplatesDir:    values.getString("PROMPT_TEMPLATES_DIR", "./templates"),
+		StoryboardEnabled:     values.getBool("STORYBOARD_ENABLED", false),
+		StoryboardShotSeconds: values.getInt("STORYBOARD_SHOT_SECONDS", 6),
+		LLMAPIKey:             values.getString("LLM_API_KEY", ""),
+		LLMBaseURL:            values.getString("LLM_BASE_URL", "http://localhost:8080/v1"),
+		LLMModel:              values.getString("LLM_MODEL", ""),
+		LLMPromptChars:        values.getInt("LLM_PROMPT_MAX_CHARS", 8000),
+		ReplicateAPIToken:     values.getString("REPLICATE_API_TOKEN", ""),
+		ReplicateBaseURL:      values.getString("REPLICATE_BASE_URL", "https://api.replicate.com/v1"),
+		ReplicateModel:        values.getString("REPLICATE_MODEL", "minimax/video-01"),
+		ReplicateV2VModel:     values.getString("REPLICATE_V2V_MODEL", "luma/modify-video"),
+		ReplicatePreferWait:   values.getBool("REPLICATE_PREFER_WAIT", true),
+		ReplicatePromptChars:  values.getInt("REPLICATE_PROMPT_MAX_CHARS", 2000),
+		ReplicateCostPerSec:   values.getFloat("REPLICATE_COST_PER_SECOND", 0.0014),
+		ReplicateModelCosts:   values.getRates("REPLICATE_MODEL_COSTS"),
+		TranscribeEnabled:     values.getBool("TRANSCRIBE_ENABLED", true),
+		WhisperDockerPath:     values.getString("WHISPER_DOCKER_PATH", "docker"),
+		WhisperDockerImage:    values.getString("WHISPER_DOCKER_IMAGE", "ghcr.io/ggml-org/whisper.cpp:main"),
+		WhisperModel:          values.getString("WHISPER_MODEL", "small"),
+		WhisperModelDir:       values.getString("WHISPER_MODEL_DIR", "./models"),
+		WhisperAutoDownload:   values.getBool("WHISPER_AUTO_DOWNLOAD", true),
+		RecordFormat:          values.getString("AUDIO_RECORD_FORMAT", "alsa"),
+		RecordDevice:          values.getString("AUDIO_RECORD_DEVICE", "default"),
+		RecordDurationSeconds: values.getInt("AUDIO_RECORD_SECONDS", 15),
+		JobPollInterval:       values.getDuration("JOB_POLL_INTERVAL", 4*time.Second),
+		JobConcurrency:        values.getInt("JOB_CONCURRENCY", 2),
+		HTTPTimeout:           values.getDuration("HTTP_TIMEOUT", 5*time.Minute),
 	}
+	cfg.Profile = values.profile
+	cfg.Files = values.files
+	cfg.Settings = values.settings()
+	cfg.options = options
+	problems := append(values.problems, values.unknownKeys()...)
+	return cfg, append(problems, cfg.validate()...), nil
 }
 
-func getEnv(key, fallback string) string {
-	value := os.Getenv(key)
+func (values *loader) getString(key, fallback string) string {
+	value := values.lookup(key, fallback)
 	if value == "" {
 		return fallback
 	}
 	return value
 }
 
-func getEnvInt(key string, fallback int) int {
-	value := os.Getenv(key)
+func (values *loader) getInt(key string, fallback int) int {
+	value := values.lookup(key, fmt.Sprint(fallback))
 	if value == "" {
 		return fallback
 	}
 	parsed, err := strconv.Atoi(value)
 	if err != nil {
+		values.invalid(key, value, "a whole number such as 15", fallback)
 		return fallback
 	}
 	return parsed
 }
 
-func getEnvDuration(key string, fallback time.Duration) time.Duration {
-	value := os.Getenv(key)
+func (values *loader) getFloat(key string, fallback float64) float64 {
+	value := values.lookup(key, fmt.Sprint(fallback))
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsInf(parsed, 0) || math.IsNaN(parsed) {
+		values.invalid(key, value, "a number such as 0.0014", fallback)
+		return fallback
+	}
+	return parsed
+}
+
+func (values *loader) getRates(key string) map[string]float64 {
+	value := values.lookup(key, "")
+	rates := map[string]float64{}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		model, raw, ok := strings.Cut(pair, "=")
+		rate, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if !ok || strings.TrimSpace(model) == "" || err != nil || rate < 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+			values.invalid(key, value, "owner/model=rate pairs such as luma/modify-video=0.0025", "none")
+			return nil
+		}
+		rates[strings.TrimSpace(model)] = rate
+	}
+	return rates
+}
+
+func (values *loader) getDuration(key string, fallback time.Duration) time.Duration {
+	value := values.lookup(key, fmt.Sprint(fallback))
 	if value == "" {
 		return fallback
 	}
 	parsed, err := time.ParseDuration(value)
 	if err != nil {
+		values.invalid(key, value, "a duration with a unit such as 5s or 5m", fallback)
 		return fallback
 	}
 	return parsed
 }
 
-func getEnvBool(key string, fallback bool) bool {
-	value := os.Getenv(key)
+func (values *loader) getBool(key string, fallback bool) bool {
+	value := values.lookup(key, fmt.Sprint(fallback))
 	if value == "" {
 		return fallback
 	}
 	parsed, err := strconv.ParseBool(value)
 	if err != nil {
+		values.invalid(key, value, "true or false", fallback)
 		return fallback
 	}
 	return parsed
@@ -0,0 +1,318 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/audio2videoAI/pkg/credentials"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+	SourceSession = "session"
+)
+
+type Options struct {
+	Path      string
+	Profile   string
+	Overrides map[string]string
+	Session   map[string]string
+}
+
+type Setting struct {
+	Key    string
+	Value  string
+	Source string
+}
+
+type fileConfig struct {
+	Profile  string                    `yaml:"profile,omitempty"`
+	Settings map[string]any            `yaml:"settings,omitempty"`
+	Profiles map[string]map[string]any `yaml:"profiles,omitempty"`
+}
+
+type loader struct {
+	profile   string
+	files     []string
+	file      map[string]string
+	overrides map[string]string
+	session   map[string]string
+	resolved  []Setting
+	problems  []Problem
+
+	credentialsErr error
+}
+
+func ProjectConfigPath() string {
+	return "a2v.yaml"
+}
+
+func UserConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "a2v", "config.yaml")
+}
+
+func newLoader(options Options) (*loader, error) {
+	values := &loader{file: map[string]string{}, overrides: map[string]string{}, session: map[string]string{}}
+	for key, value := range options.Overrides {
+		values.overrides[strings.ToUpper(key)] = value
+	}
+	for key, value := range options.Session {
+		values.session[strings.ToUpper(key)] = value
+	}
+
+	paths := []string{UserConfigPath(), ProjectConfigPath()}
+	if options.Path != "" {
+		paths = []string{options.Path}
+	}
+	var files []fileConfig
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) && options.Path == "" {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("config file: %w", err)
+		}
+		var file fileConfig
+		if err := yaml.Unmarshal(content, &file); err != nil {
+			return nil, fmt.Errorf("config file %s: %w", path, err)
+		}
+		files = append(files, file)
+		values.files = append(values.files, path)
+	}
+
+	values.profile = options.Profile
+	if values.profile == "" {
+		values.profile = os.Getenv("A2V_PROFILE")
+	}
+	for _, file := range files {
+		if values.profile == "" && file.Profile != "" {
+			values.profile = file.Profile
+		}
+		values.merge(file.Settings)
+	}
+	if values.profile != "" {
+		found := false
+		for _, file := range files {
+			if settings, ok := file.Profiles[values.profile]; ok {
+				values.merge(settings)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("config profile not found: %s", values.profile)
+		}
+	}
+	return values, nil
+}
+
+func (values *loader) merge(settings map[string]any) {
+	for key, value := range settings {
+		key = strings.ToUpper(key)
+		switch value.(type) {
+		case nil:
+			continue
+		case map[string]any:
+			values.problems = append(values.problems, Problem{Key: key, Severity: SeverityError, Message: "expected a single value, got a mapping"})
+			continue
+		case []any:
+			values.problems = append(values.problems, Problem{Key: key, Severity: SeverityError, Message: "expected a single value, got a list"})
+			continue
+		}
+		values.file[key] = fmt.Sprint(value)
+	}
+}
+
+func (values *loader) lookup(key, fallback string) string {
+	value, source := fallback, SourceDefault
+	if fileValue, ok := values.file[key]; ok {
+		value, source = fileValue, SourceFile
+	}
+	if envValue := os.Getenv(key); envValue != "" {
+		value, source = envValue, SourceEnv
+	} else if provider, ok := credentials.ProviderForKey(key); ok && values.credentialsErr == nil {
+		token, tokenSource, err := credentials.Lookup(provider)
+		if err != nil {
+			values.credentialsErr = err
+			values.problems = append(values.problems, Problem{Key: key, Severity: SeverityWarning, Message: err.Error()})
+		} else if token != "" {
+			value, source = token, tokenSource
+		}
+	}
+	if flagValue, ok := values.overrides[key]; ok {
+		value, source = flagValue, SourceFlag
+	}
+	if sessionValue, ok := values.session[key]; ok {
+		value, source = sessionValue, SourceSession
+	}
+	values.resolved = append(values.resolved, Setting{Key: key, Value: value, Source: source})
+	return value
+}
+
+func (values *loader) unknownKeys() []Problem {
+	read := map[string]bool{}
+	for _, setting := range values.resolved {
+		read[setting.Key] = true
+	}
+	for _, source := range []map[string]string{values.file, values.overrides, values.session} {
+		for key := range source {
+			if _, ok := read[key]; !ok {
+				read[key] = false
+			}
+		}
+	}
+	var problems []Problem
+	for key, known := range read {
+		if !known {
+			problems = append(problems, Problem{Key: key, Severity: SeverityWarning, Message: "unknown setting, ignored"})
+		}
+	}
+	sort.Slice(problems, func(i, j int) bool { return problems[i].Key < problems[j].Key })
+	return problems
+}
+
+func (values *loader) settings() []Setting {
+	settings := append([]Setting(nil), values.resolved...)
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Key < settings[j].Key })
+	return settings
+}
+
+func (setting Setting) Secret() bool {
+	key := strings.ToUpper(setting.Key)
+	return strings.HasSuffix(key, "_TOKEN") || strings.HasSuffix(key, "_API_KEY") || strings.HasSuffix(key, "_SECRET")
+}
+
+func (setting Setting) Masked() string {
+	if !setting.Secret() || setting.Value == "" {
+		return setting.Value
+	}
+	if len(setting.Value) <= 8 {
+		return "********"
+	}
+	return "****" + setting.Value[len(setting.Value)-4:]
+}
+
+func (cfg Config) Setting(key string) Setting {
+	for _, setting := range cfg.Settings {
+		if setting.Key == key {
+			return setting
+		}
+	}
+	return Setting{Key: key}
+}
+
+func (cfg Config) With(changes map[string]string) (Config, []Problem, error) {
+	options := cfg.options
+	options.Session = map[string]string{}
+	for key, value := range cfg.options.Session {
+		options.Session[key] = value
+	}
+	for key, value := range changes {
+		options.Session[strings.ToUpper(key)] = value
+	}
+	return Load(options)
+}
+
+func (cfg Config) SavePath() string {
+	if len(cfg.Files) > 0 {
+		return cfg.Files[len(cfg.Files)-1]
+	}
+	return ProjectConfigPath()
+}
+
+func (cfg Config) Save(changes map[string]string) (string, error) {
+	path := cfg.SavePath()
+	content, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return "", fmt.Errorf("config file %s: %w", path, err)
+	}
+	if document.Kind == 0 {
+		document = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return "", fmt.Errorf("config file %s: expected a mapping at the top level", path)
+	}
+
+	target := mappingValue(root, "settings")
+	if cfg.Profile != "" {
+		target = mappingValue(mappingValue(root, "profiles"), cfg.Profile)
+	}
+	keys := make([]string, 0, len(changes))
+	for key := range changes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		node := scalarValue(target, key)
+		node.Kind, node.Value, node.Tag, node.Style = yaml.ScalarNode, changes[key], "", 0
+		if node.ShortTag() == "!!null" {
+			node.Tag, node.Style = "!!str", yaml.DoubleQuotedStyle
+		}
+	}
+
+	var output bytes.Buffer
+	encoder := yaml.NewEncoder(&output)
+	encoder.SetIndent(indentOf(content))
+	if err := encoder.Encode(&document); err != nil {
+		return "", err
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return "", err
+		}
+	}
+	return path, os.WriteFile(path, output.Bytes(), 0o644)
+}
+
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	node := scalarValue(mapping, key)
+	if node.Kind != yaml.MappingNode {
+		*node = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", HeadComment: node.HeadComment, LineComment: node.LineComment}
+	}
+	return node
+}
+
+func scalarValue(mapping *yaml.Node, key string) *yaml.Node {
+	for index := 0; index+1 < len(mapping.Content); index += 2 {
+		if strings.EqualFold(mapping.Content[index].Value, key) {
+			return mapping.Content[index+1]
+		}
+	}
+	value := &yaml.Node{Kind: yaml.ScalarNode}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: strings.ToLower(key)}, value)
+	return value
+}
+
+func indentOf(content []byte) int {
+	for _, line := range strings.Split(string(content), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed != "" && trimmed != line && !strings.HasPrefix(trimmed, "#") {
+			return len(line) - len(trimmed)
+		}
+	}
+	return 2
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+type Problem struct {
+	Key      string
+	Severity string
+	Message  string
+}
+
+func (problem Problem) Error() string {
+	return fmt.Sprintf("%s: %s", problem.Key, problem.Message)
+}
+
+func HasErrors(problems []Problem) bool {
+	for _, problem := range problems {
+		if problem.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+func (values *loader) invalid(key, value, expected string, fallback any) {
+	values.problems = append(values.problems, Problem{
+		Key:      key,
+		Severity: SeverityError,
+		Message:  fmt.Sprintf("invalid value %q, expected %s (default is %v)", value, expected, fallback),
+	})
+}
+
+func (cfg Config) validate() []Problem {
+	var problems []Problem
+	add := func(key, severity, format string, args ...any) {
+		problems = append(problems, Problem{Key: key, Severity: severity, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if cfg.ReplicateAPIToken == "" {
+		add("REPLICATE_API_TOKEN", SeverityWarning, "not set; rendering will fail until a token is configured")
+	}
+	if cfg.StoryboardEnabled && cfg.LLMBaseURL == "" {
+		add("LLM_BASE_URL", SeverityError, "required when STORYBOARD_ENABLED=true")
+	}
+	if cfg.OutputDir == "" {
+		add("OUTPUT_DIR", SeverityError, "must not be empty")
+	}
+	if cfg.HTTPTimeout < time.Second {
+		add("HTTP_TIMEOUT", SeverityError, "%s is too short; use at least 1s", cfg.HTTPTimeout)
+	}
+	if cfg.JobPollInterval <= 0 {
+		add("JOB_POLL_INTERVAL", SeverityError, "must be positive")
+	}
+	if cfg.JobConcurrency < 1 {
+		add("JOB_CONCURRENCY", SeverityError, "must be at least 1")
+	}
+	if cfg.RecordDurationSeconds <= 0 {
+		add("AUDIO_RECORD_SECONDS", SeverityError, "must be positive")
+	}
+	if cfg.ThumbnailCount < 0 {
+		add("THUMBNAIL_COUNT", SeverityError, "must not be negative")
+	}
+	switch strings.ToLower(strings.TrimPrefix(cfg.ThumbnailFormat, ".")) {
+	case "jpg", "jpeg", "png":
+	default:
+		add("THUMBNAIL_FORMAT", SeverityError, "%q is not supported; use jpg or png", cfg.ThumbnailFormat)
+	}
+	if cfg.PreviewEnabled && (cfg.PreviewWidth <= 0 || cfg.PreviewFPS <= 0) {
+		add("PREVIEW_WIDTH", SeverityError, "PREVIEW_WIDTH and PREVIEW_FPS must be positive")
+	}
+	if cfg.StoryboardShotSeconds <= 0 {
+		add("STORYBOARD_SHOT_SECONDS", SeverityError, "must be positive")
+	}
+	if cfg.ReplicatePromptChars < 0 {
+		add("REPLICATE_PROMPT_MAX_CHARS", SeverityError, "must not be negative; use 0 for no limit")
+	}
+	if cfg.ReplicateCostPerSec < 0 {
+		add("REPLICATE_COST_PER_SECOND", SeverityError, "must not be negative")
+	}
+	if cfg.LLMPromptChars < 0 {
+		add("LLM_PROMPT_MAX_CHARS", SeverityError, "must not be negative; use 0 for no limit")
+	}
+	return problems
+}
@@ -0,0 +1,154 @@
+package credentials
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+const (
+	service       = "a2v"
+	PassphraseEnv = "A2V_CREDENTIALS_PASSPHRASE"
+
+	SourceKeyring = "keyring"
+	SourceFile    = "credentials file"
+)
+
+var ErrPassphraseRequired = errors.New("credentials file is encrypted; set " + PassphraseEnv)
+
+type KeyringError struct {
+	Err error
+}
+
+func (err *KeyringError) Error() string {
+	return fmt.Sprintf("OS keyring unavailable: %v", err.Err)
+}
+
+func (err *KeyringError) Unwrap() error {
+	return err.Err
+}
+
+var providerKeys = map[string]string{
+	"replicate":  "REPLICATE_API_TOKEN",
+	"elevenlabs": "ELEVENLABS_API_KEY",
+	"llm":        "LLM_API_KEY",
+}
+
+func Providers() []string {
+	names := make([]string, 0, len(providerKeys))
+	for name := range providerKeys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func ProviderKey(provider string) (string, error) {
+	key, ok := providerKeys[strings.ToLower(strings.TrimSpace(provider))]
+	if !ok {
+		return "", fmt.Errorf("unknown provider %q (expected %s)", provider, strings.Join(Providers(), ", "))
+	}
+	return key, nil
+}
+
+func ProviderForKey(key string) (string, bool) {
+	for provider, providerKey := range providerKeys {
+		if providerKey == key {
+			return provider, true
+		}
+	}
+	return "", false
+}
+
+func FilePath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "a2v", "credentials.enc")
+}
+
+func Save(provider, token, passphrase string) (string, error) {
+	key, err := ProviderKey(provider)
+	if err != nil {
+		return "", err
+	}
+	keyringErr := keyring.Set(service, key, token)
+	if keyringErr == nil {
+		return SourceKeyring, nil
+	}
+	if passphrase == "" {
+		return "", &KeyringError{Err: keyringErr}
+	}
+	tokens, err := readFile(FilePath(), passphrase)
+	if err != nil {
+		return "", err
+	}
+	tokens[key] = token
+	if err := writeFile(FilePath(), passphrase, tokens); err != nil {
+		return "", err
+	}
+	return SourceFile, nil
+}
+
+func Lookup(provider string) (string, string, error) {
+	key, err := ProviderKey(provider)
+	if err != nil {
+		return "", "", err
+	}
+	token, err := keyring.Get(service, key)
+	if err == nil && token != "" {
+		return token, SourceKeyring, nil
+	}
+	if _, err := os.Stat(FilePath()); err != nil {
+		return "", "", nil
+	}
+	passphrase := os.Getenv(PassphraseEnv)
+	if passphrase == "" {
+		return "", "", ErrPassphraseRequired
+	}
+	tokens, err := readFile(FilePath(), passphrase)
+	if err != nil {
+		return "", "", err
+	}
+	if token := tokens[key]; token != "" {
+		return token, SourceFile, nil
+	}
+	return "", "", nil
+}
+
+func Delete(provider, passphrase string) error {
+	key, err := ProviderKey(provider)
+	if err != nil {
+		return err
+	}
+	removed := keyring.Delete(service, key) == nil
+	if _, err := os.Stat(FilePath()); err == nil {
+		if passphrase == "" {
+			if removed {
+				return nil
+			}
+			return ErrPassphraseRequired
+		}
+		tokens, err := readFile(FilePath(), passphrase)
+		if err != nil {
+			return err
+		}
+		if _, ok := tokens[key]; ok {
+			delete(tokens, key)
+			if err := writeFile(FilePath(), passphrase, tokens); err != nil {
+				return err
+			}
+			removed = true
+		}
+	}
+	if !removed {
+		return fmt.Errorf("no stored token for %s", provider)
+	}
+	return nil
+}